@@ -4,7 +4,8 @@ import (
 	"blum-test/common/apprunner"
 	"blum-test/common/config"
 	"blum-test/common/logger"
-	"blum-test/internal/clients/fastforex"
+	"blum-test/common/signature"
+	"blum-test/internal/clients/providers"
 	"blum-test/internal/db"
 	deliveryHttp "blum-test/internal/delivery/http"
 	"blum-test/internal/repository"
@@ -24,14 +25,19 @@ import (
 // @contact.name   Abdarrakhman Akhmetgali
 // @contact.email  neversi123123@gmail.com
 
-// @BasePath  /v0
+// @BasePath  /
+
+// @securityDefinitions.apikey  AdminToken
+// @in                          header
+// @name                        Authorization
+// @description                 "Bearer <token>", admin API is enabled if HTTP_SERVER_ADMIN_TOKEN is set
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
 	cfg, err := config.LoadConfig(ctx)
 	if err != nil {
-		logger.JSONLogger.Error("parse config", err)
+		logger.JSONLogger.Error("parse config", slog.Any("error", err))
 		return
 	}
 
@@ -39,23 +45,40 @@ func main() {
 
 	dbClient, err := db.NewPostgresClient(ctx, cfg.Postgres)
 	if err != nil {
-		logger.JSONLogger.Error("initialize postgres client", err)
+		logger.JSONLogger.Error("initialize postgres client", slog.Any("error", err))
 		return
 	}
 	defer dbClient.Close()
 
 	repo := repository.NewCurrencyPostgresRepository(dbClient)
+	rateRepo := repository.NewRatePostgresRepository(dbClient)
+	quoteRepo := repository.NewQuotePostgresRepository(dbClient)
+	markupRepo := repository.NewMarkupPostgresRepository(dbClient)
+	feeRepo := repository.NewFeePostgresRepository(dbClient)
 
-	fastForexClient, err := fastforex.NewClient(cfg.FastForex)
+	rateProviders, err := providers.NewRateProviders(cfg)
 	if err != nil {
-		logger.JSONLogger.Error("initialize fast forex client", err)
+		logger.JSONLogger.Error("initialize rate providers", slog.Any("error", err))
 		return
 	}
 
 	// TODO shutdown after httpServer, maybe DI? or cascade shutdown
-	svc := service.NewRateCalculator(cfg.Service, repo, fastForexClient)
+	svc, err := service.NewRateCalculator(cfg.Service, repo, rateRepo, quoteRepo, markupRepo, feeRepo, rateProviders)
+	if err != nil {
+		logger.JSONLogger.Error("initialize rate calculator", slog.Any("error", err))
+		return
+	}
+
+	var signer *signature.Signer
+	if cfg.Signing.PrivateKeyFile != "" {
+		signer, err = signature.LoadSigner(cfg.Signing.PrivateKeyFile)
+		if err != nil {
+			logger.JSONLogger.Error("initialize signer", slog.Any("error", err))
+			return
+		}
+	}
 
-	httpServer := deliveryHttp.NewServer(cfg, svc)
+	httpServer := deliveryHttp.NewServer(cfg, svc, signer)
 
 	if err := apprunner.StartApp(
 		ctx,
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"blum-test/common/logger"
+	"blum-test/internal/clients/fastforex/fakeserver"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// Fake FastForex API to run the service without network,
+// point FAST_FOREX_BASE_URL to its address
+func main() {
+	addr := flag.String("addr", "localhost:8090", "address to listen")
+	apiKey := flag.String("api-key", "", "expected API key, empty disables the check")
+	latency := flag.Duration("latency", 0, "delay of every response")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	fake := fakeserver.NewServer(*apiKey)
+	fake.SetLatency(*latency)
+
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: fake,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.JSONLogger.Error("error while shutdowning fake server", slog.Any("error", err))
+		}
+	}()
+
+	logger.JSONLogger.Info("fake fastforex is listening", slog.String("addr", *addr))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.JSONLogger.Error("error while serving", slog.Any("error", err))
+	}
+}
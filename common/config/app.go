@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	. "blum-test/common/logger"
@@ -19,21 +20,105 @@ type AppConfig struct {
 
 	HTTPServer *HTTPServer `envconfig:"HTTP_SERVER"`
 
-	Postgres  *Postgres  `envconfig:"POSTGRES_DB"`
-	FastForex *FastForex `envconfig:"FAST_FOREX"`
+	Postgres *Postgres `envconfig:"POSTGRES_DB"`
+
+	// RateProviders is the list of rates data sources ordered by priority,
+	// rates which could not be fetched from the provider are requested
+	// from the next one
+	RateProviders  []string        `envconfig:"RATE_PROVIDERS" default:"fastforex"`
+	FastForex      *FastForex      `envconfig:"FAST_FOREX"`
+	StaticProvider *StaticProvider `envconfig:"STATIC_PROVIDER"`
+
+	Signing *Signing `envconfig:"SIGNING"`
 }
 
 type Service struct {
-	RatePollingInterval     time.Duration `envconfig:"RATE_POLLING_INTERVAL" default:"60s"`
+	RatePollingInterval time.Duration `envconfig:"RATE_POLLING_INTERVAL" default:"60s"`
+	// CurrencyPollingInterval is the period of the currencies resync
+	// while the currency events subscription is lost
 	CurrencyPollingInterval time.Duration `envconfig:"CURRENCY_POLLING_INTERVAL" default:"5s"`
+	// CurrencyUpdateDebounce is the quiet period after the last currency
+	// update before their rates are fetched, so bulk updates are fetched
+	// at once. CurrencyUpdateMaxWait caps the delay of the first pending
+	// update during the continuous updates
+	CurrencyUpdateDebounce time.Duration `envconfig:"CURRENCY_UPDATE_DEBOUNCE" default:"500ms"`
+	CurrencyUpdateMaxWait  time.Duration `envconfig:"CURRENCY_UPDATE_MAX_WAIT" default:"5s"`
+	// RateRetryMinBackoff and RateRetryMaxBackoff bound the exponential
+	// delay of rates polling retries after failures, the delay is also
+	// capped by RatePollingInterval
+	RateRetryMinBackoff time.Duration `envconfig:"RATE_RETRY_MIN_BACKOFF" default:"1s"`
+	RateRetryMaxBackoff time.Duration `envconfig:"RATE_RETRY_MAX_BACKOFF" default:"5m"`
+
+	// RateAggregation is the way rates of several providers are combined:
+	// "failover" takes the rate from the first provider by priority which
+	// supplied it, "median" and "trimmed_mean" query all providers concurrently
+	RateAggregation string `envconfig:"RATE_AGGREGATION" default:"failover"`
+	// RateMaxDeviation is the max relative deviation from the median,
+	// provider rates beyond it are dropped as outliers
+	RateMaxDeviation float64 `envconfig:"RATE_MAX_DEVIATION" default:"0.05"`
+	// RateTrimRatio is the fraction of the lowest and the highest
+	// rates dropped from each side in "trimmed_mean" mode
+	RateTrimRatio float64 `envconfig:"RATE_TRIM_RATIO" default:"0.2"`
+
+	// PivotCurrencies are the currencies ordered by priority which
+	// could be used as intermediate ones in cross rates
+	PivotCurrencies []string `envconfig:"PIVOT_CURRENCIES" default:"USD,USDT"`
+	// QuoteCurrencies overrides the currency each currency is quoted
+	// against by providers, e.g. "XMR:BTC,RUB:EUR". By default fiat
+	// currencies are quoted against USD and crypto against USDT
+	QuoteCurrencies map[string]string `envconfig:"QUOTE_CURRENCIES"`
+
+	// MaxDecimals limits decimal places of the conversion output
+	// per currency code or type, code limit takes precedence
+	MaxDecimals map[string]int32 `envconfig:"MAX_DECIMALS" default:"FIAT:8,CRYPTO:18"`
+
+	// ForbiddenPairTypes are currency types combinations which could
+	// not be converted, e.g. "FIAT/FIAT,CRYPTO/CRYPTO"
+	ForbiddenPairTypes []string `envconfig:"FORBIDDEN_PAIR_TYPES"`
+
+	// QuoteTTL is the time the quoted rate is locked for
+	QuoteTTL time.Duration `envconfig:"QUOTE_TTL" default:"60s"`
+
+	// ConvertBatchLimit is the max count of items of the batch conversion
+	ConvertBatchLimit int `envconfig:"CONVERT_BATCH_LIMIT" default:"10000"`
+
+	// RateMaxAge is the max age of the rates used for conversion,
+	// zero disables the limit
+	RateMaxAge time.Duration `envconfig:"RATE_MAX_AGE" default:"5m"`
+	// RateMaxAgeByType overrides RateMaxAge per currency type,
+	// e.g. "FIAT:1h,CRYPTO:2m"
+	RateMaxAgeByType map[string]time.Duration `envconfig:"RATE_MAX_AGE_BY_TYPE"`
+	// RateStaleMaxAge is the max age of the rates restored from the
+	// snapshots while providers are not available, such rates are
+	// flagged as stale in the responses, zero disables the limit
+	RateStaleMaxAge time.Duration `envconfig:"RATE_STALE_MAX_AGE" default:"24h"`
 }
 
+// fastForexProvider is the name of FastForex in RateProviders
+const fastForexProvider = "fastforex"
+
 type FastForex struct {
-	ApiKey         string        `envconfig:"API_KEY" required:"true"`
+	BaseURL string `envconfig:"BASE_URL" default:"https://api.fastforex.io"`
+	// ApiKey is required if fastforex is among RateProviders
+	ApiKey         string        `envconfig:"API_KEY"`
 	RequestTimeout time.Duration `envconfig:"REQUEST_TIMEOUT" default:"30s"`
 	RetriesCount   int           `envconfig:"RETRIES_COUNT" default:"3"`
 }
 
+// StaticProvider is the stand-in provider with fixed rates,
+// useful for local runs and tests without network
+type StaticProvider struct {
+	// Rates are amounts of currency per 1 USD, e.g. "EUR:0.92,ETH:0.0003"
+	Rates map[string]string `envconfig:"RATES"`
+}
+
+// Signing configures Ed25519 signing of the conversions,
+// responses are not signed if the key file is not set
+type Signing struct {
+	// PrivateKeyFile is the path to PEM encoded PKCS #8 private key
+	PrivateKeyFile string `envconfig:"PRIVATE_KEY_FILE"`
+}
+
 func getEnvFilenames() []string {
 	return []string{".env.local", ".env"}
 }
@@ -42,17 +127,31 @@ func LoadConfig(ctx context.Context) (*AppConfig, error) {
 	for _, fileName := range getEnvFilenames() {
 		err := godotenv.Load(fileName)
 		if err != nil {
-			JSONLogger.Error("error loading env file", slog.String("filename", fileName), err)
+			JSONLogger.Error("error loading env file", slog.String("filename", fileName), slog.Any("error", err))
 		}
 	}
 
 	var cfg AppConfig
 	if err := envconfig.Process("", &cfg); err != nil {
-		JSONLogger.Error("cannot process envs", err)
+		JSONLogger.Error("cannot process envs", slog.Any("error", err))
 		return nil, fmt.Errorf("cannot process envs: %w", err)
 	} else {
 		JSONLogger.Info("Config initialized")
 	}
 
+	if err := cfg.validate(); err != nil {
+		JSONLogger.Error("invalid config", slog.Any("error", err))
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return &cfg, nil
 }
+
+// validate checks the settings required by the configured providers
+func (cfg *AppConfig) validate() error {
+	if slices.Contains(cfg.RateProviders, fastForexProvider) && (cfg.FastForex == nil || cfg.FastForex.ApiKey == "") {
+		return fmt.Errorf("required key FAST_FOREX_API_KEY missing value for %s rate provider", fastForexProvider)
+	}
+
+	return nil
+}
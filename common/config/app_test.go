@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AppConfig
+		wantErr bool
+	}{
+		{
+			name: "fastforex with api key",
+			cfg: AppConfig{
+				RateProviders: []string{"fastforex"},
+				FastForex:     &FastForex{ApiKey: "key"},
+			},
+		},
+		{
+			name: "fastforex without api key",
+			cfg: AppConfig{
+				RateProviders: []string{"static", "fastforex"},
+				FastForex:     &FastForex{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fastforex without config",
+			cfg: AppConfig{
+				RateProviders: []string{"fastforex"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "api key is not required for other providers",
+			cfg: AppConfig{
+				RateProviders: []string{"static"},
+				FastForex:     &FastForex{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
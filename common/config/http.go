@@ -6,4 +6,7 @@ type HTTPServer struct {
 	Host            string        `default:"0.0.0.0"`
 	Port            uint16        `envconfig:"PORT" default:"8080"`
 	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"30s"`
+	// AdminToken is the bearer token of the admin API,
+	// the admin API is disabled if it is empty
+	AdminToken string `envconfig:"ADMIN_TOKEN"`
 }
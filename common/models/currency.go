@@ -17,17 +17,30 @@ const (
 	// Gold CurrencyType = "GOLD"
 )
 
+// IsKnown checks the type is one of the declared ones
+func (t CurrencyType) IsKnown() bool {
+	switch t {
+	case Fiat, Crypto:
+		return true
+	}
+
+	return false
+}
+
 const (
 	USD  CurrencyCode = "USD"
 	USDT CurrencyCode = "USDT"
 )
 
 type Currency struct {
-	Name      string
-	Code      CurrencyCode
-	Type      CurrencyType
-	IsEnabled bool
-	UpdatedAt time.Time
+	Name string
+	Code CurrencyCode
+	Type CurrencyType
+	// MinorUnits is the count of decimal places of the currency:
+	// ISO 4217 exponent for fiat, chain decimals for crypto
+	MinorUnits int32
+	IsEnabled  bool
+	UpdatedAt  time.Time
 }
 
 func (c *CurrencyCode) UnmarshalJSON(data []byte) error {
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 )
 
 type CurrencyPair struct {
@@ -9,7 +10,7 @@ type CurrencyPair struct {
 	Quote Currency
 }
 
-func (i CurrencyPair) Validate() error {
+func (i CurrencyPair) Validate(policy PairTypesPolicy) error {
 	if !i.Base.IsEnabled {
 		return &ErrCurrencyNotAvailable{
 			Code: i.Base.Code,
@@ -22,7 +23,7 @@ func (i CurrencyPair) Validate() error {
 		}
 	}
 
-	if i.Base.Type == i.Quote.Type {
+	if !policy.IsAllowed(i.Base.Type, i.Quote.Type) {
 		return &ErrInvalidCurrencyPair{
 			Base:  &i.Base,
 			Quote: &i.Quote,
@@ -35,3 +36,48 @@ func (i CurrencyPair) Validate() error {
 func (i CurrencyPair) String() string {
 	return fmt.Sprintf("%s/%s", i.Base.Code, i.Quote.Code)
 }
+
+// PairTypes is the combination of base and quote currencies types
+type PairTypes struct {
+	Base  CurrencyType
+	Quote CurrencyType
+}
+
+// PairTypesPolicy is the set of forbidden currency types combinations,
+// empty policy allows conversion between any currencies
+type PairTypesPolicy map[PairTypes]struct{}
+
+// NewPairTypesPolicy parses forbidden combinations in "BASE_TYPE/QUOTE_TYPE"
+// format, e.g. "FIAT/FIAT"
+func NewPairTypesPolicy(forbidden []string) (PairTypesPolicy, error) {
+	policy := make(PairTypesPolicy, len(forbidden))
+	for _, pairTypes := range forbidden {
+		base, quote, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(pairTypes)), "/")
+		if !ok || base == "" || quote == "" {
+			return nil, fmt.Errorf("invalid currency types pair \"%s\"", pairTypes)
+		}
+
+		// typo in the type would silently forbid nothing
+		for _, currencyType := range []CurrencyType{CurrencyType(base), CurrencyType(quote)} {
+			if !currencyType.IsKnown() {
+				return nil, fmt.Errorf("unknown currency type \"%s\" in pair \"%s\"", currencyType, pairTypes)
+			}
+		}
+
+		policy[PairTypes{
+			Base:  CurrencyType(base),
+			Quote: CurrencyType(quote),
+		}] = struct{}{}
+	}
+
+	return policy, nil
+}
+
+func (p PairTypesPolicy) IsAllowed(base, quote CurrencyType) bool {
+	_, forbidden := p[PairTypes{
+		Base:  base,
+		Quote: quote,
+	}]
+
+	return !forbidden
+}
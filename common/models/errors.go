@@ -9,7 +9,7 @@ type ErrInvalidCurrencyPair struct {
 
 func (e *ErrInvalidCurrencyPair) Error() string {
 	return fmt.Sprintf(
-		"currency pair \"%s/%s\" types is not compatible (%s/%s)",
+		"currency pair \"%s/%s\" types is not allowed for convertion (%s/%s)",
 		e.Base.Code, e.Quote.Code, e.Base.Type, e.Quote.Type,
 	)
 }
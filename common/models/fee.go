@@ -0,0 +1,35 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type FeeScope string
+
+const (
+	// FeePair applies to the conversions from BASE to QUOTE of the pair "BASE/QUOTE"
+	FeePair FeeScope = "PAIR"
+	// FeeType applies to the conversions with the currencies of the type,
+	// the rule has bps only as the quote currencies of the type differ
+	FeeType FeeScope = "TYPE"
+)
+
+// FeeRule is the tier of the fee schedule of the target, the tier
+// with the greatest MinAmount not above the gross amount is applied.
+// Amounts are in the quote currency of the conversion, so only PAIR
+// rules have them
+type FeeRule struct {
+	Scope FeeScope `json:"scope"`
+	// Target is the pair or the currency type
+	Target string `json:"target"`
+	// MinAmount is the lower bound of the gross amount of the tier
+	MinAmount decimal.Decimal `json:"min_amount"`
+	Flat      decimal.Decimal `json:"flat"`
+	// Bps is the percentage of the gross amount in basis points
+	Bps    int32           `json:"bps"`
+	MinFee decimal.Decimal `json:"min_fee"`
+	// MaxFee is not limited if nil
+	MaxFee *decimal.Decimal `json:"max_fee,omitempty"`
+}
+
+// FeeSchedule is the tiers of the fee rules of the same target
+// sorted by MinAmount
+type FeeSchedule []FeeRule
@@ -0,0 +1,21 @@
+package models
+
+type MarkupScope string
+
+const (
+	// MarkupPair applies to the pair "BASE/QUOTE" in both directions
+	MarkupPair MarkupScope = "PAIR"
+	// MarkupCurrency applies to the pairs with the currency
+	MarkupCurrency MarkupScope = "CURRENCY"
+	// MarkupType applies to the pairs with the currencies of the type
+	MarkupType MarkupScope = "TYPE"
+)
+
+// Markup is the half of the bid/ask spread around the mid rate
+type Markup struct {
+	Scope MarkupScope
+	// Target is the pair, the currency code or the currency type
+	Target string
+	// Bps is the markup in basis points, 1 bps is 0.01%
+	Bps int32
+}
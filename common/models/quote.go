@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote is the rate of the currency pair locked until ExpiresAt
+type Quote struct {
+	ID    string
+	Base  CurrencyCode
+	Quote CurrencyCode
+	// Side is the side of the client the rate is locked for, "mid", "sell" or "buy"
+	Side string
+	// Rate is the amount of quote currency per 1 base currency
+	// including the markup of the side
+	Rate decimal.Decimal
+	// Fees are the fee schedules in force when the quote was created,
+	// the largest fee of them is charged
+	Fees      []FeeSchedule
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func (q *Quote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateSnapshot is the rate of the currency against USD
+// at the moment it was fetched
+type RateSnapshot struct {
+	Code CurrencyCode
+	// RateInUSD is the amount of currency per 1 USD
+	RateInUSD decimal.Decimal
+	Provider  string
+	FetchedAt time.Time
+}
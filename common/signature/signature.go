@@ -0,0 +1,159 @@
+// Package signature signs the conversion results of the rate calculator
+// with Ed25519 and verifies them, so downstream services could prove
+// the rate came from the service.
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	Algorithm = "Ed25519"
+
+	// messageVersion prefixes the signed message, so its
+	// format could be changed without ambiguity
+	messageVersion = "rate-calculator/v2"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+var ErrInvalidKey = errors.New("invalid Ed25519 key")
+
+// Payload is the signed data, all the values are in the same
+// form as in the response so they could be verified as is
+type Payload struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	// Side is the side of the client the rate includes markup of
+	Side   string `json:"side,omitempty"`
+	Amount string `json:"amount,omitempty"`
+	Rate   string `json:"rate"`
+	// Gross and Fee are the breakdown of the output
+	Gross  string `json:"gross,omitempty"`
+	Fee    string `json:"fee,omitempty"`
+	Output string `json:"output,omitempty"`
+	// QuoteID and ExpiresAt are set for the quotes only
+	QuoteID   string     `json:"quote_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// Message returns the canonical form of the payload which is signed
+func (p Payload) Message() []byte {
+	expiresAt := ""
+	if p.ExpiresAt != nil {
+		expiresAt = p.ExpiresAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return []byte(strings.Join([]string{
+		messageVersion,
+		p.Base,
+		p.Quote,
+		p.Side,
+		p.Amount,
+		p.Rate,
+		p.Gross,
+		p.Fee,
+		p.Output,
+		p.QuoteID,
+		expiresAt,
+		p.Timestamp.UTC().Format(time.RFC3339Nano),
+	}, "\n"))
+}
+
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{
+		key:   key,
+		keyID: KeyID(key.Public().(ed25519.PublicKey)),
+	}
+}
+
+// LoadSigner reads PEM encoded PKCS #8 private key from the file,
+// e.g. generated by "openssl genpkey -algorithm ed25519"
+func LoadSigner(path string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	return NewSigner(edKey), nil
+}
+
+// Sign returns base64 encoded signature of the payload
+func (s *Signer) Sign(p Payload) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, p.Message()))
+}
+
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// Verify checks base64 encoded signature of the payload
+func Verify(publicKey ed25519.PublicKey, p Payload, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	if len(publicKey) != ed25519.PublicKeySize || !ed25519.Verify(publicKey, p.Message(), sig) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// KeyID identifies the key by the first bytes of its hash,
+// so the verifier could choose the key after rotation
+func KeyID(publicKey ed25519.PublicKey) string {
+	hash := sha256.Sum256(publicKey)
+	return hex.EncodeToString(hash[:8])
+}
+
+// ParsePublicKey decodes base64 encoded raw public key
+// as it is returned by the public key endpoint
+func ParsePublicKey(key string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return ed25519.PublicKey(raw), nil
+}
+
+// EncodePublicKey encodes the public key for the public key endpoint
+func EncodePublicKey(key ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
@@ -0,0 +1,197 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestSigner(t *testing.T) *Signer {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+
+	return NewSigner(key)
+}
+
+func testPayload() Payload {
+	expiresAt := time.Date(2024, 5, 1, 12, 1, 0, 0, time.UTC)
+
+	return Payload{
+		Base:      "USD",
+		Quote:     "ETH",
+		Side:      "sell",
+		Amount:    "100",
+		Rate:      "0.00028",
+		Gross:     "0.028",
+		Fee:       "0.001",
+		Output:    "0.027",
+		QuoteID:   "5f0c2c64-8a9e-4a57-9d3e-1f6b0b0a7c11",
+		ExpiresAt: &expiresAt,
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 123, time.UTC),
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	signer := newTestSigner(t)
+	payload := testPayload()
+
+	signature := signer.Sign(payload)
+	if err := Verify(signer.PublicKey(), payload, signature); err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+
+	// timestamp is compared in UTC
+	zone := time.FixedZone("UTC+3", 3*60*60)
+	payload.Timestamp = payload.Timestamp.In(zone)
+	expiresAt := payload.ExpiresAt.In(zone)
+	payload.ExpiresAt = &expiresAt
+	if err := Verify(signer.PublicKey(), payload, signature); err != nil {
+		t.Errorf("Verify() of the timestamps in other zone: %v", err)
+	}
+}
+
+func TestSignVerifyConversion(t *testing.T) {
+	signer := newTestSigner(t)
+
+	// conversions are signed without quote fields
+	payload := testPayload()
+	payload.QuoteID = ""
+	payload.ExpiresAt = nil
+
+	if err := Verify(signer.PublicKey(), payload, signer.Sign(payload)); err != nil {
+		t.Fatalf("Verify(): %v", err)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	signer := newTestSigner(t)
+	signature := signer.Sign(testPayload())
+
+	tests := []struct {
+		name   string
+		tamper func(p *Payload)
+	}{
+		{name: "base", tamper: func(p *Payload) { p.Base = "EUR" }},
+		{name: "quote", tamper: func(p *Payload) { p.Quote = "BTC" }},
+		{name: "side", tamper: func(p *Payload) { p.Side = "buy" }},
+		{name: "amount", tamper: func(p *Payload) { p.Amount = "1000" }},
+		{name: "rate", tamper: func(p *Payload) { p.Rate = "0.00029" }},
+		{name: "gross", tamper: func(p *Payload) { p.Gross = "0.029" }},
+		{name: "fee", tamper: func(p *Payload) { p.Fee = "0" }},
+		{name: "output", tamper: func(p *Payload) { p.Output = "0.029" }},
+		{name: "quote id", tamper: func(p *Payload) { p.QuoteID = "1" }},
+		{name: "expires at", tamper: func(p *Payload) {
+			expiresAt := p.ExpiresAt.Add(time.Hour)
+			p.ExpiresAt = &expiresAt
+		}},
+		{name: "no expires at", tamper: func(p *Payload) { p.ExpiresAt = nil }},
+		{name: "timestamp", tamper: func(p *Payload) { p.Timestamp = p.Timestamp.Add(time.Nanosecond) }},
+		// fields are separated, so the value could not be moved to the next one
+		{name: "shifted value", tamper: func(p *Payload) { p.Amount, p.Rate = "", "100\n0.00028" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := testPayload()
+			tt.tamper(&payload)
+
+			if err := Verify(signer.PublicKey(), payload, signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify() = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	signer := newTestSigner(t)
+	other := newTestSigner(t)
+	payload := testPayload()
+
+	tests := []struct {
+		name      string
+		publicKey ed25519.PublicKey
+		signature string
+	}{
+		{name: "other key", publicKey: other.PublicKey(), signature: signer.Sign(payload)},
+		{name: "not base64", publicKey: signer.PublicKey(), signature: "not base64!"},
+		{name: "empty signature", publicKey: signer.PublicKey(), signature: ""},
+		{name: "short key", publicKey: signer.PublicKey()[:16], signature: signer.Sign(payload)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.publicKey, payload, tt.signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify() = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestPublicKeyEncoding(t *testing.T) {
+	signer := newTestSigner(t)
+
+	key, err := ParsePublicKey(EncodePublicKey(signer.PublicKey()))
+	if err != nil {
+		t.Fatalf("ParsePublicKey(): %v", err)
+	}
+	if !key.Equal(signer.PublicKey()) {
+		t.Errorf("ParsePublicKey() = %x, want %x", key, signer.PublicKey())
+	}
+	if KeyID(key) != signer.KeyID() {
+		t.Errorf("KeyID() = %s, want %s", KeyID(key), signer.KeyID())
+	}
+
+	for _, invalid := range []string{"", "not base64!", EncodePublicKey(signer.PublicKey()[:16])} {
+		if _, err := ParsePublicKey(invalid); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("ParsePublicKey(%q) = %v, want ErrInvalidKey", invalid, err)
+		}
+	}
+}
+
+func TestLoadSigner(t *testing.T) {
+	signer := newTestSigner(t)
+
+	der, err := x509.MarshalPKCS8PrivateKey(signer.key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey(): %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}), 0o600); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+
+	loaded, err := LoadSigner(path)
+	if err != nil {
+		t.Fatalf("LoadSigner(): %v", err)
+	}
+	if loaded.KeyID() != signer.KeyID() {
+		t.Errorf("KeyID() = %s, want %s", loaded.KeyID(), signer.KeyID())
+	}
+
+	payload := testPayload()
+	if err := Verify(signer.PublicKey(), payload, loaded.Sign(payload)); err != nil {
+		t.Errorf("Verify() of the loaded signer signature: %v", err)
+	}
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidPath, []byte("not a pem"), 0o600); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+	if _, err := LoadSigner(invalidPath); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("LoadSigner() = %v, want ErrInvalidKey", err)
+	}
+}
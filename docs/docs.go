@@ -27,9 +27,652 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/convert": {
+        "/v0/admin/currencies": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Creates currency",
+                "parameters": [
+                    {
+                        "description": "currency",
+                        "name": "currency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateCurrencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "invalid currency or unknown type",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "currency already exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/v0/admin/currencies/{code}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Deletes currency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "currency code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "invalid currency code",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "currency not exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enables, disables currency or changes its type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "currency code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "changed fields",
+                        "name": "currency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.UpdateCurrencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "invalid currency or unknown type",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "currency not exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/v0/convert": {
+            "get": {
+                "description": "Converts any currency pairs except types combinations forbidden in the config",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rates"
+                ],
+                "summary": "Converts amount of base currency to quote currency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "base currency code",
+                        "name": "base",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "quote currency code",
+                        "name": "quote",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "input amount of base currency",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "round to decimals places, quote currency minor units by default",
+                        "name": "decimals",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "half_up",
+                            "half_even",
+                            "floor",
+                            "ceil",
+                            "truncate"
+                        ],
+                        "type": "string",
+                        "default": "half_up",
+                        "description": "rounding mode of the output",
+                        "name": "rounding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "include providers of the rates",
+                        "name": "sources",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "mid",
+                            "sell",
+                            "buy"
+                        ],
+                        "type": "string",
+                        "default": "mid",
+                        "description": "side of the client, sell converts by bid rate, buy by ask rate",
+                        "name": "side",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ConvertResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid parameters or forbidden currency types pair",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency or rate not exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    },
+                    "503": {
+                        "description": "rate is older than max age",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v0/convert/batch": {
+            "post": {
+                "description": "All the items are converted by the same rates, results are in the same order as the items, failed items have their own status and error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rates"
+                ],
+                "summary": "Converts amounts of the batch items",
+                "parameters": [
+                    {
+                        "description": "batch items",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/http.ConvertBatchItem"
+                            }
+                        }
+                    },
+                    {
+                        "enum": [
+                            "half_up",
+                            "half_even",
+                            "floor",
+                            "ceil",
+                            "truncate"
+                        ],
+                        "type": "string",
+                        "default": "half_up",
+                        "description": "rounding mode of all the outputs",
+                        "name": "rounding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/http.ConvertBatchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid body, rounding or batch is too large",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/v0/convert/historical": {
+            "get": {
+                "description": "Uses the latest stored rate snapshots fetched not after the moment, the output is by the mid rate without markups and fees since they are not stored",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rates"
+                ],
+                "summary": "Converts amount of base currency to quote currency at the moment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "base currency code",
+                        "name": "base",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "quote currency code",
+                        "name": "quote",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "input amount of base currency",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "moment of the conversion in RFC3339",
+                        "name": "at",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "round to decimals places, quote currency minor units by default",
+                        "name": "decimals",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "half_up",
+                            "half_even",
+                            "floor",
+                            "ceil",
+                            "truncate"
+                        ],
+                        "type": "string",
+                        "default": "half_up",
+                        "description": "rounding mode of the output",
+                        "name": "rounding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ConvertHistoricalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid or unsupported parameters, non-positive amount or forbidden currency types pair",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency or rate snapshot not exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/v0/currencies": {
+            "get": {
+                "description": "Currencies which codes are accepted by the conversion endpoints",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Lists enabled currencies",
+                "parameters": [
+                    {
+                        "enum": [
+                            "FIAT",
+                            "CRYPTO"
+                        ],
+                        "type": "string",
+                        "description": "currency type filter",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ListCurrenciesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v0/quotes": {
+            "post": {
+                "description": "Returns the quote which could be executed by the locked rate until it expires",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Locks the current rate of the pair",
+                "parameters": [
+                    {
+                        "description": "currency pair and side",
+                        "name": "quote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/http.QuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid body, side or forbidden currency types pair",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency or rate not exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    },
+                    "503": {
+                        "description": "rate is older than max age",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v0/quotes/{id}/execute": {
+            "post": {
+                "description": "The quote could be executed any number of times until it expires",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quotes"
+                ],
+                "summary": "Converts amount by the locked rate of the quote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "quote id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "amount of base currency",
+                        "name": "amount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ExecuteQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.ExecuteQuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid parameters or non-positive amount",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "quote not exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "quote expired",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency is not available anymore",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/v0/rates": {
+            "get": {
+                "description": "Rates of the symbols or of all enabled currencies, symbols without rates or forbidden for conversion are skipped",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rates"
+                ],
+                "summary": "Returns current rates against base currency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "base currency code",
+                        "name": "base",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated currencies codes",
+                        "name": "symbols",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.RatesResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "currency or rate not exists",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/v0/signing/public-key": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "signing"
+                ],
+                "summary": "Returns the public key of the responses signatures",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.PublicKeyResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "signing is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/convert": {
             "get": {
-                "description": "Converts Fiat/Crypto and Crypto/Fiat currency pairs",
+                "description": "Exact decimal conversion, amount and output are decimal strings",
                 "produces": [
                     "application/json"
                 ],
@@ -53,7 +696,7 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "number",
+                        "type": "string",
                         "description": "input amount of base currency",
                         "name": "amount",
                         "in": "query",
@@ -61,21 +704,53 @@ var doc = `{
                     },
                     {
                         "type": "integer",
-                        "default": 5,
-                        "description": "round up to decimals places",
+                        "description": "round to decimals places, quote currency minor units by default",
                         "name": "decimals",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "half_up",
+                            "half_even",
+                            "floor",
+                            "ceil",
+                            "truncate"
+                        ],
+                        "type": "string",
+                        "default": "half_up",
+                        "description": "rounding mode of the output",
+                        "name": "rounding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "include providers of the rates",
+                        "name": "sources",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "mid",
+                            "sell",
+                            "buy"
+                        ],
+                        "type": "string",
+                        "default": "mid",
+                        "description": "side of the client, sell converts by bid rate, buy by ask rate",
+                        "name": "side",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.ConvertResponse"
+                            "$ref": "#/definitions/http.ConvertResponseV1"
                         }
                     },
                     "400": {
-                        "description": "invalid parameters",
+                        "description": "invalid parameters or forbidden currency types pair",
                         "schema": {
                             "$ref": "#/definitions/http.ErrorResponse"
                         }
@@ -88,17 +763,311 @@ var doc = `{
                     },
                     "500": {
                         "description": ""
+                    },
+                    "503": {
+                        "description": "rate is older than max age",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "signature.Payload": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "base": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "string"
+                },
+                "gross": {
+                    "description": "Gross and Fee are the breakdown of the output",
+                    "type": "string"
+                },
+                "output": {
+                    "type": "string"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "quote_id": {
+                    "description": "QuoteID and ExpiresAt are set for the quotes only",
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "side": {
+                    "description": "Side is the side of the client the rate includes markup of",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.ConvertBatchItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 100
+                },
+                "base": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "decimals": {
+                    "description": "Decimals is the count of decimal places of the output,\nquote currency minor units by default",
+                    "type": "integer",
+                    "example": 5
+                },
+                "quote": {
+                    "type": "string",
+                    "example": "ETH"
+                },
+                "side": {
+                    "description": "Side is the side of the client, mid by default",
+                    "type": "string",
+                    "enum": [
+                        "mid",
+                        "sell",
+                        "buy"
+                    ],
+                    "example": "sell"
+                }
+            }
+        },
+        "http.ConvertBatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "number"
+                },
+                "gross": {
+                    "type": "number"
+                },
+                "output": {
+                    "description": "Output is the net amount after the fee",
+                    "type": "number"
+                },
+                "path": {
+                    "description": "Path is the chain of currencies the cross rate was calculated through",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "description": "Status is the status the item would have as a single conversion",
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "http.ConvertHistoricalResponse": {
+            "type": "object",
+            "properties": {
+                "output": {
+                    "type": "number"
+                },
+                "snapshot_time": {
+                    "description": "SnapshotTime is the fetch time of the rates the output was calculated by",
+                    "type": "string"
+                }
+            }
+        },
         "http.ConvertResponse": {
             "type": "object",
             "properties": {
+                "fee": {
+                    "type": "number"
+                },
+                "gross": {
+                    "type": "number"
+                },
                 "output": {
+                    "description": "Output is the net amount after the fee",
                     "type": "number"
+                },
+                "path": {
+                    "description": "Path is the chain of currencies the cross rate was calculated through",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate_timestamp": {
+                    "description": "RateTimestamp is the fetch time of the oldest quote used,\nomitted for the same currency conversion",
+                    "type": "string"
+                },
+                "signature": {
+                    "description": "Signature is filled only if signing is configured",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.SignatureResponse"
+                        }
+                    ]
+                },
+                "sources": {
+                    "description": "Sources are filled only if requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.RateSource"
+                    }
+                },
+                "stale": {
+                    "description": "Stale is set if the rate is restored from the persisted snapshots\nbecause the providers are not available",
+                    "type": "boolean"
+                }
+            }
+        },
+        "http.ConvertResponseV1": {
+            "type": "object",
+            "properties": {
+                "ask": {
+                    "type": "string",
+                    "example": "0.0003519"
+                },
+                "bid": {
+                    "type": "string",
+                    "example": "0.0003505"
+                },
+                "fee": {
+                    "type": "string",
+                    "example": "0.0001"
+                },
+                "gross": {
+                    "type": "string",
+                    "example": "0.03522"
+                },
+                "output": {
+                    "description": "Output is the net amount after the fee",
+                    "type": "string",
+                    "example": "0.03512"
+                },
+                "path": {
+                    "description": "Path is the chain of currencies the cross rate was calculated through",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate": {
+                    "description": "Rate is the rate of the requested side the output was converted by",
+                    "type": "string",
+                    "example": "0.0003512"
+                },
+                "rate_timestamp": {
+                    "description": "RateTimestamp is the fetch time of the oldest quote used,\nomitted for the same currency conversion",
+                    "type": "string"
+                },
+                "signature": {
+                    "description": "Signature is filled only if signing is configured",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.SignatureResponse"
+                        }
+                    ]
+                },
+                "sources": {
+                    "description": "Sources are filled only if requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.RateSourceV1"
+                    }
+                },
+                "stale": {
+                    "description": "Stale is set if the rate is restored from the persisted snapshots\nbecause the providers are not available",
+                    "type": "boolean"
+                }
+            }
+        },
+        "http.CreateCurrencyRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "BTC"
+                },
+                "is_enabled": {
+                    "description": "IsEnabled is true by default",
+                    "type": "boolean",
+                    "example": true
+                },
+                "minor_units": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Bitcoin"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "CRYPTO"
+                }
+            }
+        },
+        "http.CreateQuoteRequest": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "quote": {
+                    "type": "string",
+                    "example": "ETH"
+                },
+                "side": {
+                    "description": "Side is the side of the client, sell locks the bid rate, buy the ask\nrate, mid by default",
+                    "type": "string",
+                    "enum": [
+                        "mid",
+                        "sell",
+                        "buy"
+                    ],
+                    "example": "sell"
+                }
+            }
+        },
+        "http.CurrencyResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "ETH"
+                },
+                "minor_units": {
+                    "description": "MinorUnits is the default precision of the conversion output",
+                    "type": "integer",
+                    "example": 18
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ethereum"
+                },
+                "rate_available": {
+                    "description": "RateAvailable is set if the currency could be converted now",
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "CRYPTO"
                 }
             }
         },
@@ -109,6 +1078,234 @@ var doc = `{
                     "type": "string"
                 }
             }
+        },
+        "http.ExecuteQuoteRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100.25"
+                },
+                "decimals": {
+                    "description": "Decimals is the count of decimal places of the output,\nquote currency minor units by default",
+                    "type": "integer",
+                    "example": 5
+                },
+                "rounding": {
+                    "type": "string",
+                    "example": "half_up"
+                }
+            }
+        },
+        "http.ExecuteQuoteResponse": {
+            "type": "object",
+            "properties": {
+                "fee": {
+                    "type": "string",
+                    "example": "0.0001"
+                },
+                "gross": {
+                    "type": "string",
+                    "example": "0.03522"
+                },
+                "output": {
+                    "description": "Output is the net amount after the fee",
+                    "type": "string",
+                    "example": "0.03512"
+                },
+                "quote_id": {
+                    "type": "string",
+                    "example": "5f0c2c64-8a9e-4a57-9d3e-1f6b0b0a7c11"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "0.0003512"
+                },
+                "signature": {
+                    "description": "Signature is filled only if signing is configured",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.SignatureResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "http.ListCurrenciesResponse": {
+            "type": "object",
+            "properties": {
+                "currencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.CurrencyResponse"
+                    }
+                }
+            }
+        },
+        "http.PublicKeyResponse": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "example": "Ed25519"
+                },
+                "key_id": {
+                    "type": "string",
+                    "example": "3f2a9c0d1e4b5a67"
+                },
+                "public_key": {
+                    "description": "PublicKey is base64 encoded raw public key",
+                    "type": "string"
+                }
+            }
+        },
+        "http.QuoteResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f0c2c64-8a9e-4a57-9d3e-1f6b0b0a7c11"
+                },
+                "quote": {
+                    "type": "string",
+                    "example": "ETH"
+                },
+                "rate": {
+                    "description": "Rate is the amount of quote currency per 1 base currency\nincluding the markup of the side",
+                    "type": "string",
+                    "example": "0.0003512"
+                },
+                "side": {
+                    "type": "string",
+                    "example": "sell"
+                },
+                "signature": {
+                    "description": "Signature is filled only if signing is configured",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.SignatureResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "http.RateResponse": {
+            "type": "object",
+            "properties": {
+                "rate": {
+                    "type": "number",
+                    "example": 0.92
+                },
+                "stale": {
+                    "description": "Stale is set if the rate is restored from the persisted snapshots",
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "description": "Timestamp is the fetch time of the oldest quote used",
+                    "type": "string"
+                }
+            }
+        },
+        "http.RateSource": {
+            "type": "object",
+            "properties": {
+                "pair": {
+                    "type": "string"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "spread": {
+                    "type": "number"
+                }
+            }
+        },
+        "http.RateSourceV1": {
+            "type": "object",
+            "properties": {
+                "pair": {
+                    "type": "string"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate": {
+                    "type": "string"
+                },
+                "spread": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.RatesResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "rates": {
+                    "description": "Rates are amounts of the symbol currencies per 1 base currency",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/http.RateResponse"
+                    }
+                }
+            }
+        },
+        "http.SignatureResponse": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string",
+                    "example": "Ed25519"
+                },
+                "key_id": {
+                    "type": "string",
+                    "example": "3f2a9c0d1e4b5a67"
+                },
+                "payload": {
+                    "$ref": "#/definitions/signature.Payload"
+                },
+                "value": {
+                    "description": "Value is base64 encoded signature",
+                    "type": "string"
+                }
+            }
+        },
+        "http.UpdateCurrencyRequest": {
+            "type": "object",
+            "properties": {
+                "is_enabled": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "type": {
+                    "type": "string",
+                    "example": "CRYPTO"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "\"Bearer \u003ctoken\u003e\", admin API is enabled if HTTP_SERVER_ADMIN_TOKEN is set",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
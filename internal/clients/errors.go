@@ -0,0 +1,27 @@
+package clients
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrRateLimited is returned by the providers when their quota is
+// exceeded, RetryAfter is zero if the provider did not specify it
+type ErrRateLimited struct {
+	Provider   string
+	RetryAfter time.Duration
+	// Err is the provider specific error
+	Err error
+}
+
+func (e *ErrRateLimited) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: %s, retry after %s", e.Provider, e.Err, e.RetryAfter)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Provider, e.Err)
+}
+
+func (e *ErrRateLimited) Unwrap() error {
+	return e.Err
+}
@@ -3,12 +3,15 @@ package fastforex
 import (
 	"blum-test/common/config"
 	"blum-test/common/models"
+	"blum-test/internal/clients"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	jsoniter "github.com/json-iterator/go"
@@ -17,6 +20,8 @@ import (
 const (
 	fastForexBaseURL     = "https://api.fastforex.io"
 	fastForexApiKeyParam = "api_key"
+
+	ProviderName = "fastforex"
 )
 
 type Client struct {
@@ -29,9 +34,14 @@ func NewClient(cfg *config.FastForex) (*Client, error) {
 		return nil, ErrInvalidAPIKey
 	}
 
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = fastForexBaseURL
+	}
+
 	cli := resty.New().
 		SetTimeout(cfg.RequestTimeout).
-		SetBaseURL(fastForexBaseURL).
+		SetBaseURL(baseURL).
 		SetRetryCount(cfg.RetriesCount).
 		SetQueryParam(fastForexApiKeyParam, cfg.ApiKey)
 
@@ -47,20 +57,20 @@ func NewClient(cfg *config.FastForex) (*Client, error) {
 	return c, nil
 }
 
+func (c *Client) Name() string {
+	return ProviderName
+}
+
 const cryptoPairsLimitPerRequest = 10
 const workersCount = 3
 
-type CryptoRatesResponse struct {
-	Rates map[models.CurrencyCode]json.Number
-}
-
 func (c *Client) GetCryptoRates(
 	ctx context.Context,
 	bases []models.CurrencyCode,
 	quote models.CurrencyCode,
-) (*CryptoRatesResponse, error) {
+) (*clients.RatesResponse, error) {
 	if len(bases) == 0 {
-		return &CryptoRatesResponse{
+		return &clients.RatesResponse{
 			Rates: make(map[models.CurrencyCode]json.Number),
 		}, nil
 	}
@@ -81,8 +91,9 @@ func (c *Client) GetCryptoRates(
 	}
 
 	type result struct {
-		rates map[string]json.Number
-		err   error
+		request string
+		rates   map[string]json.Number
+		err     error
 	}
 
 	requests := make(chan string, len(requestsParam))
@@ -104,20 +115,20 @@ func (c *Client) GetCryptoRates(
 				return resp, err
 			},
 		); err != nil {
-			return result{nil, fmt.Errorf("could not make a request: %w", err)}
+			return result{request, nil, fmt.Errorf("could not make a request: %w", err)}
 		}
 		defer resp.RawBody().Close()
 
 		if resp.StatusCode() == http.StatusTooManyRequests {
-			return result{nil, ErrRateLimit}
+			return result{request, nil, newErrRateLimited(resp)}
 		}
 
 		if resp.StatusCode() == http.StatusUnauthorized {
-			return result{nil, ErrInvalidAPIKey}
+			return result{request, nil, ErrInvalidAPIKey}
 		}
 
 		if resp.StatusCode() != http.StatusOK {
-			return result{nil, fmt.Errorf("error response /crypto/fetch-prices: %v", resp.String())}
+			return result{request, nil, fmt.Errorf("error response /crypto/fetch-prices: %v", resp.String())}
 		}
 
 		var payload struct {
@@ -125,10 +136,10 @@ func (c *Client) GetCryptoRates(
 		}
 
 		if err := jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(resp.Body(), &payload); err != nil {
-			return result{nil, fmt.Errorf("error while decoding crypto rates: %w", err)}
+			return result{request, nil, fmt.Errorf("error while decoding crypto rates: %w", err)}
 		}
 
-		return result{payload.Prices, nil}
+		return result{request, payload.Prices, nil}
 	}
 
 	worker := func(ctx context.Context, requests <-chan string, results chan<- result) {
@@ -147,14 +158,18 @@ func (c *Client) GetCryptoRates(
 
 	close(requests)
 
-	res := CryptoRatesResponse{
+	res := clients.RatesResponse{
 		Rates: make(map[models.CurrencyCode]json.Number),
 	}
 
+	// failed batches do not affect the others, their
+	// errors are returned along with the fetched rates
+	errs := []error{}
 	for i := 0; i < len(requestsParam); i++ {
 		result := <-results
 		if result.err != nil {
-			return nil, fmt.Errorf("error while requesting crypto rates: %w", result.err)
+			errs = append(errs, fmt.Errorf("error while requesting crypto rates %s: %w", result.request, result.err))
+			continue
 		}
 
 		for pair, rate := range result.rates {
@@ -163,20 +178,16 @@ func (c *Client) GetCryptoRates(
 		}
 	}
 
-	return &res, nil
-}
-
-type FiatRatesResponse struct {
-	Rates map[models.CurrencyCode]json.Number `json:"results"`
+	return &res, errors.Join(errs...)
 }
 
 func (c *Client) GetFiatRates(
 	ctx context.Context,
 	base models.CurrencyCode,
 	quotes []models.CurrencyCode,
-) (*FiatRatesResponse, error) {
+) (*clients.RatesResponse, error) {
 	if len(quotes) == 0 {
-		return &FiatRatesResponse{
+		return &clients.RatesResponse{
 			Rates: make(map[models.CurrencyCode]json.Number),
 		}, nil
 	}
@@ -195,7 +206,7 @@ func (c *Client) GetFiatRates(
 		func() (*resty.Response, error) {
 			resp, err = c.cli.R().SetQueryParams(
 				map[string]string{
-					"from": "USD",
+					"from": string(base),
 					"to":   toParam.String(),
 				},
 			).
@@ -209,7 +220,7 @@ func (c *Client) GetFiatRates(
 	}
 
 	if resp.StatusCode() == http.StatusTooManyRequests {
-		return nil, ErrRateLimit
+		return nil, newErrRateLimited(resp)
 	}
 
 	if resp.StatusCode() == http.StatusUnauthorized {
@@ -220,11 +231,33 @@ func (c *Client) GetFiatRates(
 		return nil, fmt.Errorf("error response /fetch-multi: %v", resp.String())
 	}
 
-	var payload FiatRatesResponse
+	var payload struct {
+		Results map[models.CurrencyCode]json.Number `json:"results"`
+	}
 
 	if err := jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(resp.Body(), &payload); err != nil {
 		return nil, fmt.Errorf("error while decoding fiat rates: %w", err)
 	}
 
-	return &payload, nil
+	return &clients.RatesResponse{
+		Rates: payload.Results,
+	}, nil
+}
+
+// newErrRateLimited wraps ErrRateLimit with the delay from Retry-After
+// header, which is either seconds count or HTTP date
+func newErrRateLimited(resp *resty.Response) error {
+	err := &clients.ErrRateLimited{
+		Provider: ProviderName,
+		Err:      ErrRateLimit,
+	}
+
+	retryAfter := resp.Header().Get("Retry-After")
+	if seconds, parseErr := strconv.Atoi(retryAfter); parseErr == nil && seconds > 0 {
+		err.RetryAfter = time.Duration(seconds) * time.Second
+	} else if date, parseErr := http.ParseTime(retryAfter); parseErr == nil {
+		err.RetryAfter = max(time.Until(date), 0)
+	}
+
+	return err
 }
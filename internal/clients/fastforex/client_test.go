@@ -0,0 +1,235 @@
+package fastforex
+
+import (
+	"blum-test/common/config"
+	"blum-test/common/models"
+	"blum-test/internal/clients"
+	"blum-test/internal/clients/fastforex/fakeserver"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const testAPIKey = "test-key"
+
+func newTestClient(t *testing.T, timeout time.Duration) (*Client, *fakeserver.Server) {
+	t.Helper()
+
+	fake := fakeserver.NewServer(testAPIKey)
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	cli, err := NewClient(&config.FastForex{
+		BaseURL:        srv.URL,
+		ApiKey:         testAPIKey,
+		RequestTimeout: timeout,
+	})
+	if err != nil {
+		t.Fatalf("NewClient(): %v", err)
+	}
+	fake.Reset()
+
+	return cli, fake
+}
+
+func TestGetFiatRates(t *testing.T) {
+	cli, _ := newTestClient(t, time.Second)
+
+	res, err := cli.GetFiatRates(context.Background(), models.USD, []models.CurrencyCode{"EUR", "CNY", "XXX"})
+	if err != nil {
+		t.Fatalf("GetFiatRates(): %v", err)
+	}
+
+	if len(res.Rates) != 2 {
+		t.Fatalf("rates count = %d, want 2: %v", len(res.Rates), res.Rates)
+	}
+	if rate := res.Rates["EUR"].String(); rate != "0.92" {
+		t.Errorf("EUR rate = %s, want 0.92", rate)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{
+			name:       "seconds",
+			retryAfter: "30",
+			min:        30 * time.Second,
+			max:        30 * time.Second,
+		},
+		{
+			name:       "http date",
+			retryAfter: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat),
+			min:        50 * time.Second,
+			max:        time.Minute,
+		},
+		{
+			name:       "date in the past",
+			retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat),
+		},
+		{
+			name: "not specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, fake := newTestClient(t, time.Second)
+
+			resp := fakeserver.Response{
+				Status: http.StatusTooManyRequests,
+				Body:   `{"error":"Rate limit exceeded"}`,
+			}
+			if tt.retryAfter != "" {
+				resp.Header = map[string]string{"Retry-After": tt.retryAfter}
+			}
+			fake.Enqueue(fakeserver.FetchMultiPath, resp)
+
+			_, err := cli.GetFiatRates(context.Background(), models.USD, []models.CurrencyCode{"EUR"})
+
+			var rateLimited *clients.ErrRateLimited
+			if !errors.As(err, &rateLimited) {
+				t.Fatalf("error = %v, want ErrRateLimited", err)
+			}
+			if !errors.Is(err, ErrRateLimit) {
+				t.Errorf("error = %v, want wrapped ErrRateLimit", err)
+			}
+			if rateLimited.RetryAfter < tt.min || rateLimited.RetryAfter > tt.max {
+				t.Errorf("RetryAfter = %s, want from %s to %s", rateLimited.RetryAfter, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    fakeserver.Response
+		wantErr error
+	}{
+		{
+			name:    "unauthorized",
+			resp:    fakeserver.Unauthorized(),
+			wantErr: ErrInvalidAPIKey,
+		},
+		{
+			name: "malformed json",
+			resp: fakeserver.Malformed(),
+		},
+		{
+			name: "server error",
+			resp: fakeserver.Response{
+				Status: http.StatusInternalServerError,
+				Body:   `{"error":"Internal error"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" fiat", func(t *testing.T) {
+			cli, fake := newTestClient(t, time.Second)
+			fake.Enqueue(fakeserver.FetchMultiPath, tt.resp)
+
+			res, err := cli.GetFiatRates(context.Background(), models.USD, []models.CurrencyCode{"EUR"})
+			if err == nil {
+				t.Fatalf("GetFiatRates() = %v, want error", res)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+
+		t.Run(tt.name+" crypto", func(t *testing.T) {
+			cli, fake := newTestClient(t, time.Second)
+			fake.Enqueue(fakeserver.CryptoFetchPricesPath, tt.resp)
+
+			res, err := cli.GetCryptoRates(context.Background(), []models.CurrencyCode{"ETH"}, models.USDT)
+			if err == nil {
+				t.Fatalf("GetCryptoRates() = %v, want error", res)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if len(res.Rates) != 0 {
+				t.Errorf("rates = %v, want none", res.Rates)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	const latency = 5 * time.Second
+
+	cli, fake := newTestClient(t, 50*time.Millisecond)
+	fake.SetLatency(latency)
+
+	start := time.Now()
+	_, err := cli.GetFiatRates(context.Background(), models.USD, []models.CurrencyCode{"EUR"})
+	if err == nil {
+		t.Fatal("GetFiatRates() succeeded, want timeout error")
+	}
+	// the timed out attempts are retried by the backoff, each
+	// of them is cut by the request timeout
+	if elapsed := time.Since(start); elapsed >= latency {
+		t.Errorf("request took %s, want to be cut by timeout", elapsed)
+	}
+}
+
+func TestLatencyWithinTimeout(t *testing.T) {
+	cli, fake := newTestClient(t, time.Second)
+	fake.SetLatency(50 * time.Millisecond)
+
+	res, err := cli.GetCryptoRates(context.Background(), []models.CurrencyCode{"ETH"}, models.USDT)
+	if err != nil {
+		t.Fatalf("GetCryptoRates(): %v", err)
+	}
+	if _, ok := res.Rates["ETH"]; !ok {
+		t.Errorf("rates = %v, want ETH", res.Rates)
+	}
+}
+
+func TestGetCryptoRatesPartialBatchFailure(t *testing.T) {
+	cli, fake := newTestClient(t, time.Second)
+
+	// enough currencies for several batches of cryptoPairsLimitPerRequest
+	count := 2*cryptoPairsLimitPerRequest + 1
+	rates := map[models.CurrencyCode]decimal.Decimal{}
+	codes := []models.CurrencyCode{}
+	for i := 0; i < count; i++ {
+		code := models.CurrencyCode(fmt.Sprintf("C%02d", i))
+		rates[code] = decimal.NewFromInt(int64(i + 1))
+		codes = append(codes, code)
+	}
+	fake.SetRates(rates)
+
+	fake.Enqueue(fakeserver.CryptoFetchPricesPath, fakeserver.Response{
+		Status: http.StatusInternalServerError,
+		Body:   `{"error":"Internal error"}`,
+	})
+
+	res, err := cli.GetCryptoRates(context.Background(), codes, models.USDT)
+	if err == nil {
+		t.Fatal("GetCryptoRates() succeeded, want error of the failed batch")
+	}
+	if res == nil {
+		t.Fatal("GetCryptoRates() returned no rates, want rates of the other batches")
+	}
+
+	if requests := fake.Requests(fakeserver.CryptoFetchPricesPath); requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+
+	// one of the batches failed, it is either full or the last one
+	if got := len(res.Rates); got != count-cryptoPairsLimitPerRequest && got != count-1 {
+		t.Errorf("rates count = %d, want rates of the succeeded batches", got)
+	}
+}
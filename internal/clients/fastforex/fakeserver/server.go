@@ -0,0 +1,297 @@
+package fakeserver
+
+import (
+	"blum-test/common/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FetchMultiPath        = "/fetch-multi"
+	CryptoFetchPricesPath = "/crypto/fetch-prices"
+
+	apiKeyParam = "api_key"
+)
+
+// Response is the scripted response of the server. Empty Body is replaced
+// with the regular response built from the server rates.
+type Response struct {
+	Status int
+	Body   string
+	Header map[string]string
+	Delay  time.Duration
+}
+
+// RateLimited mimics FastForex response on exceeded quota
+func RateLimited(retryAfter time.Duration) Response {
+	return Response{
+		Status: http.StatusTooManyRequests,
+		Body:   `{"error":"Rate limit exceeded"}`,
+		Header: map[string]string{
+			"Retry-After": decimal.NewFromFloat(retryAfter.Seconds()).Ceil().String(),
+		},
+	}
+}
+
+// Unauthorized mimics FastForex response on invalid API key
+func Unauthorized() Response {
+	return Response{
+		Status: http.StatusUnauthorized,
+		Body:   `{"error":"Invalid API key"}`,
+	}
+}
+
+// Malformed returns successful response with broken JSON body
+func Malformed() Response {
+	return Response{
+		Status: http.StatusOK,
+		Body:   `{"results":{"EUR":`,
+	}
+}
+
+// Server is the fake FastForex API implementing /fetch-multi and
+// /crypto/fetch-prices endpoints, it allows to run the service and
+// its tests without network. Responses could be scripted per endpoint.
+type Server struct {
+	apiKey string
+
+	mu sync.Mutex
+	// rates are amounts of currency per 1 USD
+	rates    map[models.CurrencyCode]decimal.Decimal
+	latency  time.Duration
+	scripts  map[string][]Response
+	requests map[string]int
+}
+
+// NewServer creates fake server with DefaultRates, empty apiKey
+// disables API key check
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		apiKey:   apiKey,
+		rates:    make(map[models.CurrencyCode]decimal.Decimal),
+		scripts:  make(map[string][]Response),
+		requests: make(map[string]int),
+	}
+	s.SetRates(DefaultRates())
+
+	return s
+}
+
+// DefaultRates returns rates of the currencies from the SQL seed
+func DefaultRates() map[models.CurrencyCode]decimal.Decimal {
+	return map[models.CurrencyCode]decimal.Decimal{
+		"EUR":  decimal.RequireFromString("0.92"),
+		"CNY":  decimal.RequireFromString("7.24"),
+		"USDT": decimal.RequireFromString("1.0002"),
+		"USDC": decimal.RequireFromString("0.9999"),
+		"ETH":  decimal.RequireFromString("0.00028"),
+	}
+}
+
+// SetRates replaces rates of the provided currencies, rates are
+// amounts of currency per 1 USD
+func (s *Server) SetRates(rates map[models.CurrencyCode]decimal.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for code, rate := range rates {
+		s.rates[code] = rate
+	}
+	s.rates[models.USD] = decimal.NewFromInt(1)
+}
+
+// SetLatency delays all the responses
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// Enqueue adds responses which are served in order by the endpoint
+// before returning to the regular behaviour
+func (s *Server) Enqueue(path string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scripts[path] = append(s.scripts[path], responses...)
+}
+
+// Requests returns count of requests received by the endpoint
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[path]
+}
+
+// Reset drops scripted responses, latency and requests counters
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = 0
+	s.scripts = make(map[string][]Response)
+	s.requests = make(map[string]int)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	if path != FetchMultiPath && path != CryptoFetchPricesPath {
+		writeResponse(w, Response{
+			Status: http.StatusNotFound,
+			Body:   `{"error":"Not found"}`,
+		})
+		return
+	}
+
+	resp, scripted := s.nextResponse(path)
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if !scripted && s.apiKey != "" && r.URL.Query().Get(apiKeyParam) != s.apiKey {
+		resp = Unauthorized()
+	}
+
+	if resp.Body == "" && resp.Status == http.StatusOK {
+		switch path {
+		case FetchMultiPath:
+			resp.Body = s.fetchMulti(r)
+		case CryptoFetchPricesPath:
+			resp.Body = s.fetchPrices(r)
+		}
+	}
+
+	writeResponse(w, resp)
+}
+
+func (s *Server) nextResponse(path string) (Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[path]++
+
+	if script := s.scripts[path]; len(script) > 0 {
+		s.scripts[path] = script[1:]
+
+		resp := script[0]
+		if resp.Status == 0 {
+			resp.Status = http.StatusOK
+		}
+		if resp.Delay == 0 {
+			resp.Delay = s.latency
+		}
+		return resp, true
+	}
+
+	return Response{
+		Status: http.StatusOK,
+		Delay:  s.latency,
+	}, false
+}
+
+func (s *Server) fetchMulti(r *http.Request) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	base := models.CurrencyCode(strings.ToUpper(r.URL.Query().Get("from")))
+	if base == "" {
+		base = models.USD
+	}
+
+	results := map[models.CurrencyCode]json.Number{}
+	baseRate, ok := s.rates[base]
+	if ok && !baseRate.IsZero() {
+		for _, code := range splitCodes(r.URL.Query().Get("to")) {
+			if rate, ok := s.rates[code]; ok {
+				results[code] = json.Number(rate.Div(baseRate).String())
+			}
+		}
+	}
+
+	return marshal(map[string]any{
+		"base":    base,
+		"results": results,
+		"updated": time.Now().UTC().Format(time.DateTime),
+		"ms":      1,
+	})
+}
+
+func (s *Server) fetchPrices(r *http.Request) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prices := map[string]json.Number{}
+	for _, pair := range strings.Split(r.URL.Query().Get("pairs"), ",") {
+		codes := splitPair(pair)
+		if len(codes) != 2 {
+			continue
+		}
+
+		baseRate, ok := s.rates[codes[0]]
+		if !ok || baseRate.IsZero() {
+			continue
+		}
+		quoteRate, ok := s.rates[codes[1]]
+		if !ok {
+			continue
+		}
+
+		prices[pair] = json.Number(quoteRate.Div(baseRate).String())
+	}
+
+	return marshal(map[string]any{
+		"prices": prices,
+		"ms":     1,
+	})
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	for key, value := range resp.Header {
+		w.Header().Set(key, value)
+	}
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write([]byte(resp.Body))
+}
+
+func marshal(v any) string {
+	res, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error())
+	}
+
+	return string(res)
+}
+
+func splitCodes(param string) []models.CurrencyCode {
+	res := []models.CurrencyCode{}
+	for _, code := range strings.Split(param, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			res = append(res, models.CurrencyCode(strings.ToUpper(code)))
+		}
+	}
+
+	return res
+}
+
+func splitPair(pair string) []models.CurrencyCode {
+	res := []models.CurrencyCode{}
+	for _, code := range strings.Split(pair, "/") {
+		res = append(res, models.CurrencyCode(strings.ToUpper(strings.TrimSpace(code))))
+	}
+
+	return res
+}
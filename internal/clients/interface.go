@@ -0,0 +1,33 @@
+package clients
+
+import (
+	"blum-test/common/models"
+	"context"
+	"encoding/json"
+)
+
+// IRateProvider is a source of currencies rates. Every data feed
+// (FastForex, internal price feeds etc.) should implement it in order
+// to be used by the rate calculator service.
+type IRateProvider interface {
+	// Name returns unique name of the provider, the same one
+	// which is used to select provider in the config
+	Name() string
+	// GetFiatRates returns rates of quotes currencies against base currency
+	GetFiatRates(
+		ctx context.Context,
+		base models.CurrencyCode,
+		quotes []models.CurrencyCode,
+	) (*RatesResponse, error)
+	// GetCryptoRates returns rates of bases currencies against quote currency,
+	// rates which were fetched could be returned along with the error
+	GetCryptoRates(
+		ctx context.Context,
+		bases []models.CurrencyCode,
+		quote models.CurrencyCode,
+	) (*RatesResponse, error)
+}
+
+type RatesResponse struct {
+	Rates map[models.CurrencyCode]json.Number
+}
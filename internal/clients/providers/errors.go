@@ -0,0 +1,16 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNoProviders = errors.New("no rate providers configured")
+
+type ErrUnknownProvider struct {
+	Name string
+}
+
+func (e *ErrUnknownProvider) Error() string {
+	return fmt.Sprintf("rate provider \"%s\" is not registered", e.Name)
+}
@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"blum-test/common/config"
+	"blum-test/internal/clients"
+	"blum-test/internal/clients/fastforex"
+	"blum-test/internal/clients/static"
+	"fmt"
+	"sync"
+)
+
+// Factory creates rate provider from the application config
+type Factory func(cfg *config.AppConfig) (clients.IRateProvider, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{
+		fastforex.ProviderName: func(cfg *config.AppConfig) (clients.IRateProvider, error) {
+			return fastforex.NewClient(cfg.FastForex)
+		},
+		static.ProviderName: func(cfg *config.AppConfig) (clients.IRateProvider, error) {
+			return static.NewProviderFromConfig(cfg.StaticProvider)
+		},
+	}
+)
+
+// Register makes rate provider available by the name, so it could
+// be selected through the config without changing the service.
+// Registering the same name twice replaces the previous factory.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	factories[name] = factory
+}
+
+// NewRateProviders creates rate providers listed in the config
+// keeping their priority order
+func NewRateProviders(cfg *config.AppConfig) ([]clients.IRateProvider, error) {
+	if len(cfg.RateProviders) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	res := make([]clients.IRateProvider, 0, len(cfg.RateProviders))
+	for _, name := range cfg.RateProviders {
+		provider, err := NewRateProvider(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, provider)
+	}
+
+	return res, nil
+}
+
+// NewRateProvider creates rate provider registered with the name
+func NewRateProvider(name string, cfg *config.AppConfig) (clients.IRateProvider, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, &ErrUnknownProvider{
+			Name: name,
+		}
+	}
+
+	provider, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %q provider: %w", name, err)
+	}
+
+	return provider, nil
+}
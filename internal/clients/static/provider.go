@@ -0,0 +1,137 @@
+package static
+
+import (
+	"blum-test/common/config"
+	"blum-test/common/models"
+	"blum-test/internal/clients"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+const ProviderName = "static"
+
+// Provider is the in-memory rate provider with fixed rates. It is
+// used as a local stand-in for real providers, the rates and the
+// returned error could be changed at any time.
+type Provider struct {
+	name string
+
+	mu sync.RWMutex
+	// rates are amounts of currency per 1 USD
+	rates map[models.CurrencyCode]decimal.Decimal
+	err   error
+}
+
+func NewProvider(name string, rates map[models.CurrencyCode]decimal.Decimal) *Provider {
+	p := &Provider{
+		name:  name,
+		rates: make(map[models.CurrencyCode]decimal.Decimal),
+	}
+	p.SetRates(rates)
+
+	return p
+}
+
+func NewProviderFromConfig(cfg *config.StaticProvider) (*Provider, error) {
+	rates := make(map[models.CurrencyCode]decimal.Decimal)
+	if cfg != nil {
+		for code, rateStr := range cfg.Rates {
+			rate, err := decimal.NewFromString(rateStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rate for %s: %w", code, err)
+			}
+			rates[models.CurrencyCode(strings.ToUpper(code))] = rate
+		}
+	}
+
+	return NewProvider(ProviderName, rates), nil
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// SetRates replaces rates of the provided currencies
+func (p *Provider) SetRates(rates map[models.CurrencyCode]decimal.Decimal) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for code, rate := range rates {
+		p.rates[code] = rate
+	}
+	p.rates[models.USD] = decimal.NewFromInt(1)
+}
+
+// SetError makes all subsequent requests fail with err,
+// nil err restores normal behaviour
+func (p *Provider) SetError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
+func (p *Provider) GetFiatRates(
+	ctx context.Context,
+	base models.CurrencyCode,
+	quotes []models.CurrencyCode,
+) (*clients.RatesResponse, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	res := &clients.RatesResponse{
+		Rates: make(map[models.CurrencyCode]json.Number),
+	}
+
+	baseRate, ok := p.rates[base]
+	if !ok || baseRate.IsZero() {
+		return res, nil
+	}
+
+	for _, quote := range quotes {
+		if quoteRate, ok := p.rates[quote]; ok {
+			res.Rates[quote] = json.Number(quoteRate.Div(baseRate).String())
+		}
+	}
+
+	return res, nil
+}
+
+func (p *Provider) GetCryptoRates(
+	ctx context.Context,
+	bases []models.CurrencyCode,
+	quote models.CurrencyCode,
+) (*clients.RatesResponse, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	res := &clients.RatesResponse{
+		Rates: make(map[models.CurrencyCode]json.Number),
+	}
+
+	quoteRate, ok := p.rates[quote]
+	if !ok {
+		return res, nil
+	}
+
+	for _, base := range bases {
+		if baseRate, ok := p.rates[base]; ok && !baseRate.IsZero() {
+			res.Rates[base] = json.Number(quoteRate.Div(baseRate).String())
+		}
+	}
+
+	return res, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx"
+	pgxv4 "github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -24,5 +25,13 @@ func NewPostgresClient(ctx context.Context, cfg *config.Postgres) (*pgxpool.Pool
 }
 
 func CheckErrNoRows(err error) bool {
-	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows)
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) || errors.Is(err, pgxv4.ErrNoRows)
+}
+
+// uniqueViolationCode is SQLSTATE of the unique constraint violation
+const uniqueViolationCode = "23505"
+
+func CheckErrUniqueViolation(err error) bool {
+	var pgErr interface{ SQLState() string }
+	return errors.As(err, &pgErr) && pgErr.SQLState() == uniqueViolationCode
 }
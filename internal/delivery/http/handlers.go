@@ -2,13 +2,17 @@ package http
 
 import (
 	"blum-test/common/models"
+	"blum-test/internal/repository"
 	"blum-test/internal/service"
 	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/shopspring/decimal"
 )
 
 type ErrorResponse struct {
@@ -16,76 +20,266 @@ type ErrorResponse struct {
 }
 
 type ConvertResponse struct {
+	// Output is the net amount after the fee
 	Output float64 `json:"output"`
+	Gross  float64 `json:"gross"`
+	Fee    float64 `json:"fee"`
+	// Path is the chain of currencies the cross rate was calculated through
+	Path []string `json:"path"`
+	// Stale is set if the rate is restored from the persisted snapshots
+	// because the providers are not available
+	Stale bool `json:"stale"`
+	// RateTimestamp is the fetch time of the oldest quote used,
+	// omitted for the same currency conversion
+	RateTimestamp *time.Time `json:"rate_timestamp,omitempty"`
+	// Sources are filled only if requested
+	Sources []RateSource `json:"sources,omitempty"`
+	// Signature is filled only if signing is configured
+	Signature *SignatureResponse `json:"signature,omitempty"`
+}
+
+type RateSource struct {
+	Pair      string   `json:"pair"`
+	Providers []string `json:"providers"`
+	Spread    float64  `json:"spread"`
 }
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
 // Convert
 // @Summary      Converts amount of base currency to quote currency
-// @Description  Converts Fiat/Crypto and Crypto/Fiat currency pairs
+// @Description  Converts any currency pairs except types combinations forbidden in the config
 // @Tags         rates
 // @Produce      json
 // @Param        base      query     string   true   "base currency code"             example(USD)
 // @Param        quote     query     string   true   "quote currency code"            example(ETH)
 // @Param        amount    query     number   true   "input amount of base currency"  example(100)
-// @Param        decimals  query     integer  false  "round up to decimals places"    example(5)  default(5)
+// @Param        decimals  query     integer  false  "round to decimals places, quote currency minor units by default"  example(5)
+// @Param        rounding  query     string   false  "rounding mode of the output"    Enums(half_up, half_even, floor, ceil, truncate)  default(half_up)
+// @Param        sources   query     boolean  false  "include providers of the rates" default(false)
+// @Param        side      query     string   false  "side of the client, sell converts by bid rate, buy by ask rate"  Enums(mid, sell, buy)  default(mid)
 // @Success      200       {object}  ConvertResponse
-// @Failure      400       {object}  ErrorResponse  "invalid parameters"
+// @Failure      400       {object}  ErrorResponse  "invalid parameters or forbidden currency types pair"
 // @Failure      422       {object}  ErrorResponse  "currency or rate not exists"
 // @Failure      500
-// @Router       /convert [get]
+// @Failure      503       {object}  ErrorResponse  "rate is older than max age"
+// @Router       /v0/convert [get]
 func (s *Server) Convert(c *fiber.Ctx) error {
 	base := c.Query("base")
 	quote := c.Query("quote")
 	amountStr := c.Query("amount")
-	decimalsStr := c.Query("decimals")
 
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amountDecimal, err := parseFloatAmount(amountStr)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
 			Error: err.Error(),
 		})
 	}
 
-	var decimals int64 = 5
+	params, err := parseConvertParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
 
-	if decimalsStr != "" {
-		decimals, err = strconv.ParseInt(decimalsStr, 10, 64)
-		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
-				Error: err.Error(),
-			})
+	res, err := s.svc.Convert(c.Context(), service.ConvertRequest{
+		Base:     base,
+		Quote:    quote,
+		Amount:   amountDecimal,
+		Decimals: params.decimals,
+		Rounding: params.rounding,
+		Side:     params.side,
+	})
+	if err != nil {
+		return sendConvertError(c, err)
+	}
+
+	output, _ := res.Output.Float64()
+	gross, _ := res.Charge.Gross.Float64()
+	fee, _ := res.Charge.Fee.Float64()
+
+	resp := ConvertResponse{
+		Output:        output,
+		Gross:         gross,
+		Fee:           fee,
+		Path:          newPath(res.Rate, res.Path),
+		Stale:         res.Rate.Stale,
+		RateTimestamp: newRateTimestamp(res.Rate),
+	}
+
+	if params.sources {
+		resp.Sources = make([]RateSource, 0, len(res.Path))
+		for _, rate := range res.Path {
+			resp.Sources = append(resp.Sources, newRateSource(rate))
 		}
 	}
 
-	res, err := s.svc.Convert(c.Context(), base, quote, amount, decimals)
+	resp.Signature = s.sign(newConversionPayload(amountDecimal, res))
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// parseFloatAmount parses the amount of v0 endpoints, which are limited
+// by float precision. Amount is not validated, so the float clients keep
+// working as before
+func parseFloatAmount(amountStr string) (decimal.Decimal, error) {
+	amount, err := strconv.ParseFloat(amountStr, 64)
 	if err != nil {
-		if errors.Is(err, service.ErrServiceInternal) {
-			return c.SendStatus(fiber.ErrInternalServerError.Code)
-		}
-		if errors.Is(err, service.ErrInvalidInternalRate) {
-			return c.Status(http.StatusUnprocessableEntity).JSON(ErrorResponse{
-				Error: err.Error(),
-			})
+		return decimal.Zero, err
+	}
+
+	// NaN and infinities could not be represented by decimal
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return decimal.Zero, &strconv.NumError{
+			Func: "ParseFloat",
+			Num:  amountStr,
+			Err:  strconv.ErrSyntax,
 		}
-		var currencyNotAvailable *models.ErrCurrencyNotAvailable
-		if errors.As(err, &currencyNotAvailable) {
-			return c.Status(http.StatusUnprocessableEntity).JSON(ErrorResponse{
-				Error: err.Error(),
-			})
+	}
+
+	return decimal.NewFromFloat(amount), nil
+}
+
+type convertParams struct {
+	decimals *int64
+	rounding service.RoundingMode
+	sources  bool
+	side     service.Side
+}
+
+func parseConvertParams(c *fiber.Ctx) (*convertParams, error) {
+	decimalsStr := c.Query("decimals")
+	roundingStr := c.Query("rounding")
+	sourcesStr := c.Query("sources")
+	sideStr := c.Query("side")
+
+	params := convertParams{}
+
+	var err error
+
+	if decimalsStr != "" {
+		decimals, err := strconv.ParseInt(decimalsStr, 10, 64)
+		if err != nil {
+			return nil, err
 		}
-		var invalidCurrencyPair *models.ErrInvalidCurrencyPair
-		if errors.As(err, &invalidCurrencyPair) {
-			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-				Error: err.Error(),
-			})
+		params.decimals = &decimals
+	}
+
+	params.rounding, err = service.ParseRoundingMode(roundingStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if sourcesStr != "" {
+		params.sources, err = strconv.ParseBool(sourcesStr)
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	params.side, err = service.ParseSide(sideStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &params, nil
+}
 
-		return c.SendStatus(http.StatusInternalServerError)
+func sendConvertError(c *fiber.Ctx, err error) error {
+	status := convertErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		return c.SendStatus(status)
 	}
 
-	return c.Status(http.StatusOK).JSON(ConvertResponse{
-		Output: res,
+	return c.Status(status).JSON(ErrorResponse{
+		Error: err.Error(),
 	})
 }
+
+// convertErrorStatus maps the conversion error to the response status
+func convertErrorStatus(err error) int {
+	if errors.Is(err, service.ErrServiceInternal) {
+		return http.StatusInternalServerError
+	}
+	if errors.Is(err, service.ErrInvalidInternalRate) {
+		return http.StatusUnprocessableEntity
+	}
+	var rateIsTooOld *service.ErrRateIsTooOld
+	if errors.As(err, &rateIsTooOld) {
+		return http.StatusServiceUnavailable
+	}
+	var rateNotAvailable *service.ErrRateIsNotAvailable
+	if errors.As(err, &rateNotAvailable) {
+		return http.StatusUnprocessableEntity
+	}
+	var currencyNotAvailable *models.ErrCurrencyNotAvailable
+	if errors.As(err, &currencyNotAvailable) {
+		return http.StatusUnprocessableEntity
+	}
+	var noConversionPath *service.ErrNoConversionPath
+	if errors.As(err, &noConversionPath) {
+		return http.StatusUnprocessableEntity
+	}
+	var noRateSnapshot *service.ErrNoRateSnapshot
+	if errors.As(err, &noRateSnapshot) {
+		return http.StatusUnprocessableEntity
+	}
+	var invalidCurrencyPair *models.ErrInvalidCurrencyPair
+	if errors.As(err, &invalidCurrencyPair) {
+		return http.StatusBadRequest
+	}
+	var invalidDecimals *service.ErrInvalidDecimals
+	if errors.As(err, &invalidDecimals) {
+		return http.StatusBadRequest
+	}
+	var invalidAmount *service.ErrInvalidAmount
+	if errors.As(err, &invalidAmount) {
+		return http.StatusBadRequest
+	}
+	var unknownSide *service.ErrUnknownSide
+	if errors.As(err, &unknownSide) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, repository.ErrQuoteNotFound) {
+		return http.StatusNotFound
+	}
+	var quoteExpired *service.ErrQuoteExpired
+	if errors.As(err, &quoteExpired) {
+		return http.StatusGone
+	}
+	var batchTooLarge *service.ErrBatchTooLarge
+	if errors.As(err, &batchTooLarge) {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+func newPath(rate service.Rate, path []service.Rate) []string {
+	res := []string{string(rate.Base)}
+	for _, step := range path {
+		res = append(res, string(step.Quote))
+	}
+
+	return res
+}
+
+func newRateTimestamp(rate service.Rate) *time.Time {
+	if rate.FetchedAt.IsZero() {
+		return nil
+	}
+
+	timestamp := rate.FetchedAt.UTC()
+	return &timestamp
+}
+
+func newRateSource(rate service.Rate) RateSource {
+	spread, _ := rate.Spread.Float64()
+
+	return RateSource{
+		Pair:      rate.Pair(),
+		Providers: rate.Providers,
+		Spread:    spread,
+	}
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"blum-test/common/models"
+	"blum-test/internal/repository"
+	"blum-test/internal/service"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateCurrencyRequest struct {
+	Code       string `json:"code" example:"BTC"`
+	Name       string `json:"name" example:"Bitcoin"`
+	Type       string `json:"type" example:"CRYPTO"`
+	MinorUnits int32  `json:"minor_units" example:"8"`
+	// IsEnabled is true by default
+	IsEnabled *bool `json:"is_enabled,omitempty" example:"true"`
+}
+
+// UpdateCurrencyRequest changes only the passed fields
+type UpdateCurrencyRequest struct {
+	IsEnabled *bool   `json:"is_enabled,omitempty" example:"false"`
+	Type      *string `json:"type,omitempty" example:"CRYPTO"`
+}
+
+// adminAuth checks the bearer token of the admin API
+func (s *Server) adminAuth(c *fiber.Ctx) error {
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.HTTPServer.AdminToken)) != 1 {
+		return c.Status(http.StatusUnauthorized).JSON(ErrorResponse{
+			Error: "invalid admin token",
+		})
+	}
+
+	return c.Next()
+}
+
+// CreateCurrency
+// @Summary      Creates currency
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        currency  body      CreateCurrencyRequest  true  "currency"
+// @Success      201
+// @Failure      400       {object}  ErrorResponse  "invalid currency or unknown type"
+// @Failure      401       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse  "currency already exists"
+// @Failure      500
+// @Router       /v0/admin/currencies [post]
+func (s *Server) CreateCurrency(c *fiber.Ctx) error {
+	req := CreateCurrencyRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if err := s.svc.CreateCurrency(c.Context(), models.Currency{
+		Name:       req.Name,
+		Code:       models.CurrencyCode(req.Code),
+		Type:       models.CurrencyType(req.Type),
+		MinorUnits: req.MinorUnits,
+	}, req.IsEnabled); err != nil {
+		return sendAdminError(c, err)
+	}
+
+	return c.SendStatus(http.StatusCreated)
+}
+
+// UpdateCurrency
+// @Summary      Enables, disables currency or changes its type
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        code      path      string                 true  "currency code"  example(BTC)
+// @Param        currency  body      UpdateCurrencyRequest  true  "changed fields"
+// @Success      204
+// @Failure      400       {object}  ErrorResponse  "invalid currency or unknown type"
+// @Failure      401       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse  "currency not exists"
+// @Failure      500
+// @Router       /v0/admin/currencies/{code} [patch]
+func (s *Server) UpdateCurrency(c *fiber.Ctx) error {
+	code := c.Params("code")
+
+	req := UpdateCurrencyRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if err := s.svc.UpdateCurrency(c.Context(), code, req.IsEnabled, req.Type); err != nil {
+		return sendAdminError(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// DeleteCurrency
+// @Summary      Deletes currency
+// @Tags         admin
+// @Produce      json
+// @Security     AdminToken
+// @Param        code  path      string  true  "currency code"  example(BTC)
+// @Success      204
+// @Failure      400   {object}  ErrorResponse  "invalid currency code"
+// @Failure      401   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse  "currency not exists"
+// @Failure      500
+// @Router       /v0/admin/currencies/{code} [delete]
+func (s *Server) DeleteCurrency(c *fiber.Ctx) error {
+	if err := s.svc.DeleteCurrency(c.Context(), c.Params("code")); err != nil {
+		return sendAdminError(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func sendAdminError(c *fiber.Ctx, err error) error {
+	var invalidCurrency *service.ErrInvalidCurrency
+	if errors.As(err, &invalidCurrency) {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	var unknownCurrencyType *repository.ErrUnknownCurrencyType
+	if errors.As(err, &unknownCurrencyType) {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	var currencyExists *repository.ErrCurrencyExists
+	if errors.As(err, &currencyExists) {
+		return c.Status(http.StatusConflict).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if errors.Is(err, repository.ErrCurrencyNotFound) {
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.SendStatus(http.StatusInternalServerError)
+}
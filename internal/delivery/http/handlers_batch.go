@@ -0,0 +1,110 @@
+package http
+
+import (
+	"blum-test/internal/service"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+)
+
+type ConvertBatchItem struct {
+	Base   string          `json:"base" example:"USD"`
+	Quote  string          `json:"quote" example:"ETH"`
+	Amount decimal.Decimal `json:"amount" swaggertype:"number" example:"100"`
+	// Decimals is the count of decimal places of the output,
+	// quote currency minor units by default
+	Decimals *int64 `json:"decimals,omitempty" example:"5"`
+	// Side is the side of the client, mid by default
+	Side string `json:"side,omitempty" example:"sell" enums:"mid,sell,buy"`
+}
+
+type ConvertBatchResult struct {
+	// Status is the status the item would have as a single conversion
+	Status int `json:"status" example:"200"`
+	// Output is the net amount after the fee
+	Output *float64 `json:"output,omitempty"`
+	Gross  *float64 `json:"gross,omitempty"`
+	Fee    *float64 `json:"fee,omitempty"`
+	// Path is the chain of currencies the cross rate was calculated through
+	Path  []string `json:"path,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// ConvertBatch
+// @Summary      Converts amounts of the batch items
+// @Description  All the items are converted by the same rates, results are in the same order as the items, failed items have their own status and error
+// @Tags         rates
+// @Accept       json
+// @Produce      json
+// @Param        batch     body      []ConvertBatchItem  true   "batch items"
+// @Param        rounding  query     string              false  "rounding mode of all the outputs"  Enums(half_up, half_even, floor, ceil, truncate)  default(half_up)
+// @Success      200       {array}   ConvertBatchResult
+// @Failure      400       {object}  ErrorResponse  "invalid body, rounding or batch is too large"
+// @Failure      500
+// @Router       /v0/convert/batch [post]
+func (s *Server) ConvertBatch(c *fiber.Ctx) error {
+	items := []ConvertBatchItem{}
+	if err := c.BodyParser(&items); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	rounding, err := service.ParseRoundingMode(c.Query("rounding"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	reqs := make([]service.ConvertRequest, 0, len(items))
+	for _, item := range items {
+		// invalid side fails the item only
+		reqs = append(reqs, service.ConvertRequest{
+			Base:     item.Base,
+			Quote:    item.Quote,
+			Amount:   item.Amount,
+			Decimals: item.Decimals,
+			Rounding: rounding,
+			Side:     service.Side(item.Side),
+		})
+	}
+
+	results, err := s.svc.ConvertBatch(c.Context(), reqs)
+	if err != nil {
+		return sendConvertError(c, err)
+	}
+
+	resp := make([]ConvertBatchResult, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, newConvertBatchResult(result))
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func newConvertBatchResult(result service.BatchResult) ConvertBatchResult {
+	if result.Err != nil {
+		status := convertErrorStatus(result.Err)
+		res := ConvertBatchResult{
+			Status: status,
+		}
+		if status != http.StatusInternalServerError {
+			res.Error = result.Err.Error()
+		}
+		return res
+	}
+
+	output, _ := result.Conversion.Output.Float64()
+	gross, _ := result.Conversion.Charge.Gross.Float64()
+	fee, _ := result.Conversion.Charge.Fee.Float64()
+
+	return ConvertBatchResult{
+		Status: http.StatusOK,
+		Output: &output,
+		Gross:  &gross,
+		Fee:    &fee,
+		Path:   newPath(result.Conversion.Rate, result.Conversion.Path),
+	}
+}
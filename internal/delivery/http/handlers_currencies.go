@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ListCurrenciesResponse struct {
+	Currencies []CurrencyResponse `json:"currencies"`
+}
+
+type CurrencyResponse struct {
+	Code string `json:"code" example:"ETH"`
+	Name string `json:"name" example:"Ethereum"`
+	Type string `json:"type" example:"CRYPTO"`
+	// MinorUnits is the default precision of the conversion output
+	MinorUnits int32 `json:"minor_units" example:"18"`
+	// RateAvailable is set if the currency could be converted now
+	RateAvailable bool `json:"rate_available"`
+}
+
+// ListCurrencies
+// @Summary      Lists enabled currencies
+// @Description  Currencies which codes are accepted by the conversion endpoints
+// @Tags         currencies
+// @Produce      json
+// @Param        type  query     string  false  "currency type filter"  Enums(FIAT, CRYPTO)
+// @Success      200   {object}  ListCurrenciesResponse
+// @Router       /v0/currencies [get]
+func (s *Server) ListCurrencies(c *fiber.Ctx) error {
+	currencies := s.svc.ListCurrencies(c.Query("type"))
+
+	resp := ListCurrenciesResponse{
+		Currencies: make([]CurrencyResponse, 0, len(currencies)),
+	}
+	for _, currency := range currencies {
+		resp.Currencies = append(resp.Currencies, CurrencyResponse{
+			Code:          string(currency.Code),
+			Name:          currency.Name,
+			Type:          string(currency.Type),
+			MinorUnits:    currency.MinorUnits,
+			RateAvailable: currency.RateAvailable,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
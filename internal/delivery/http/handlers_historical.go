@@ -0,0 +1,88 @@
+package http
+
+import (
+	"blum-test/internal/service"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ConvertHistoricalResponse struct {
+	Output float64 `json:"output"`
+	// SnapshotTime is the fetch time of the rates the output was calculated by
+	SnapshotTime time.Time `json:"snapshot_time"`
+}
+
+// ConvertHistorical
+// @Summary      Converts amount of base currency to quote currency at the moment
+// @Description  Uses the latest stored rate snapshots fetched not after the moment, the output is by the mid rate without markups and fees since they are not stored
+// @Tags         rates
+// @Produce      json
+// @Param        base      query     string   true   "base currency code"             example(USD)
+// @Param        quote     query     string   true   "quote currency code"            example(ETH)
+// @Param        amount    query     number   true   "input amount of base currency"  example(100)
+// @Param        at        query     string   true   "moment of the conversion in RFC3339"  example(2024-05-01T12:00:00Z)
+// @Param        decimals  query     integer  false  "round to decimals places, quote currency minor units by default"  example(5)
+// @Param        rounding  query     string   false  "rounding mode of the output"    Enums(half_up, half_even, floor, ceil, truncate)  default(half_up)
+// @Success      200       {object}  ConvertHistoricalResponse
+// @Failure      400       {object}  ErrorResponse  "invalid or unsupported parameters, non-positive amount or forbidden currency types pair"
+// @Failure      422       {object}  ErrorResponse  "currency or rate snapshot not exists"
+// @Failure      500
+// @Router       /v0/convert/historical [get]
+func (s *Server) ConvertHistorical(c *fiber.Ctx) error {
+	base := c.Query("base")
+	quote := c.Query("quote")
+	amountStr := c.Query("amount")
+	atStr := c.Query("at")
+
+	amount, err := parseFloatAmount(amountStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	at, err := time.Parse(time.RFC3339, atStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	// markups and fees history is not stored, sources are not
+	// tracked by the snapshots
+	for _, param := range []string{"side", "sources"} {
+		if c.Query(param) != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: fmt.Sprintf("parameter \"%s\" is not supported by historical conversion", param),
+			})
+		}
+	}
+
+	params, err := parseConvertParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	res, err := s.svc.ConvertHistorical(c.Context(), service.ConvertRequest{
+		Base:     base,
+		Quote:    quote,
+		Amount:   amount,
+		Decimals: params.decimals,
+		Rounding: params.rounding,
+	}, at)
+	if err != nil {
+		return sendConvertError(c, err)
+	}
+
+	output, _ := res.Output.Float64()
+
+	return c.Status(http.StatusOK).JSON(ConvertHistoricalResponse{
+		Output:       output,
+		SnapshotTime: res.SnapshotTime.UTC(),
+	})
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"blum-test/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+)
+
+// Quotes keep rates and amounts as decimal strings, since
+// the locked rate should be reproduced exactly
+
+type CreateQuoteRequest struct {
+	Base  string `json:"base" example:"USD"`
+	Quote string `json:"quote" example:"ETH"`
+	// Side is the side of the client, sell locks the bid rate, buy the ask
+	// rate, mid by default
+	Side string `json:"side,omitempty" example:"sell" enums:"mid,sell,buy"`
+}
+
+type QuoteResponse struct {
+	ID    string `json:"id" example:"5f0c2c64-8a9e-4a57-9d3e-1f6b0b0a7c11"`
+	Base  string `json:"base" example:"USD"`
+	Quote string `json:"quote" example:"ETH"`
+	Side  string `json:"side" example:"sell"`
+	// Rate is the amount of quote currency per 1 base currency
+	// including the markup of the side
+	Rate      string    `json:"rate" example:"0.0003512"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Signature is filled only if signing is configured
+	Signature *SignatureResponse `json:"signature,omitempty"`
+}
+
+type ExecuteQuoteRequest struct {
+	Amount decimal.Decimal `json:"amount" swaggertype:"string" example:"100.25"`
+	// Decimals is the count of decimal places of the output,
+	// quote currency minor units by default
+	Decimals *int64 `json:"decimals,omitempty" example:"5"`
+	Rounding string `json:"rounding" example:"half_up"`
+}
+
+type ExecuteQuoteResponse struct {
+	QuoteID string `json:"quote_id" example:"5f0c2c64-8a9e-4a57-9d3e-1f6b0b0a7c11"`
+	// Output is the net amount after the fee
+	Output string `json:"output" example:"0.03512"`
+	Gross  string `json:"gross" example:"0.03522"`
+	Fee    string `json:"fee" example:"0.0001"`
+	Rate   string `json:"rate" example:"0.0003512"`
+	// Signature is filled only if signing is configured
+	Signature *SignatureResponse `json:"signature,omitempty"`
+}
+
+// CreateQuote
+// @Summary      Locks the current rate of the pair
+// @Description  Returns the quote which could be executed by the locked rate until it expires
+// @Tags         quotes
+// @Accept       json
+// @Produce      json
+// @Param        quote  body      CreateQuoteRequest  true  "currency pair and side"
+// @Success      201    {object}  QuoteResponse
+// @Failure      400    {object}  ErrorResponse  "invalid body, side or forbidden currency types pair"
+// @Failure      422    {object}  ErrorResponse  "currency or rate not exists"
+// @Failure      500
+// @Failure      503    {object}  ErrorResponse  "rate is older than max age"
+// @Router       /v0/quotes [post]
+func (s *Server) CreateQuote(c *fiber.Ctx) error {
+	req := CreateQuoteRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	side, err := service.ParseSide(req.Side)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	quote, err := s.svc.CreateQuote(c.Context(), req.Base, req.Quote, side)
+	if err != nil {
+		return sendConvertError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(QuoteResponse{
+		ID:        quote.ID,
+		Base:      string(quote.Base),
+		Quote:     string(quote.Quote),
+		Side:      quote.Side,
+		Rate:      quote.Rate.String(),
+		ExpiresAt: quote.ExpiresAt.UTC(),
+		Signature: s.sign(newQuotePayload(quote)),
+	})
+}
+
+// ExecuteQuote
+// @Summary      Converts amount by the locked rate of the quote
+// @Description  The quote could be executed any number of times until it expires
+// @Tags         quotes
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string               true  "quote id"
+// @Param        amount  body      ExecuteQuoteRequest  true  "amount of base currency"
+// @Success      200     {object}  ExecuteQuoteResponse
+// @Failure      400     {object}  ErrorResponse  "invalid parameters or non-positive amount"
+// @Failure      404     {object}  ErrorResponse  "quote not exists"
+// @Failure      410     {object}  ErrorResponse  "quote expired"
+// @Failure      422     {object}  ErrorResponse  "currency is not available anymore"
+// @Failure      500
+// @Router       /v0/quotes/{id}/execute [post]
+func (s *Server) ExecuteQuote(c *fiber.Ctx) error {
+	req := ExecuteQuoteRequest{}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	rounding, err := service.ParseRoundingMode(req.Rounding)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	res, err := s.svc.ExecuteQuote(c.Context(), c.Params("id"), service.ConvertRequest{
+		Amount:   req.Amount,
+		Decimals: req.Decimals,
+		Rounding: rounding,
+	})
+	if err != nil {
+		return sendConvertError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ExecuteQuoteResponse{
+		QuoteID:   res.Quote.ID,
+		Output:    res.Output.StringFixed(res.Decimals),
+		Gross:     res.Charge.Gross.StringFixed(res.Decimals),
+		Fee:       res.Charge.Fee.StringFixed(res.Decimals),
+		Rate:      res.Quote.Rate.String(),
+		Signature: s.sign(newExecutionPayload(req.Amount, res)),
+	})
+}
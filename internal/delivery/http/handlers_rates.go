@@ -0,0 +1,66 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RatesResponse struct {
+	Base string `json:"base" example:"USD"`
+	// Rates are amounts of the symbol currencies per 1 base currency
+	Rates map[string]RateResponse `json:"rates"`
+}
+
+type RateResponse struct {
+	Rate float64 `json:"rate" example:"0.92"`
+	// Timestamp is the fetch time of the oldest quote used
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// Stale is set if the rate is restored from the persisted snapshots
+	Stale bool `json:"stale"`
+}
+
+// Rates
+// @Summary      Returns current rates against base currency
+// @Description  Rates of the symbols or of all enabled currencies, symbols without rates or forbidden for conversion are skipped
+// @Tags         rates
+// @Produce      json
+// @Param        base     query     string  true   "base currency code"                 example(USD)
+// @Param        symbols  query     string  false  "comma separated currencies codes"  example(EUR,ETH)
+// @Success      200      {object}  RatesResponse
+// @Failure      422      {object}  ErrorResponse  "currency or rate not exists"
+// @Failure      500
+// @Router       /v0/rates [get]
+func (s *Server) Rates(c *fiber.Ctx) error {
+	base := c.Query("base")
+	symbolsStr := c.Query("symbols")
+
+	symbols := []string{}
+	for _, symbol := range strings.Split(symbolsStr, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	rates, err := s.svc.Rates(base, symbols)
+	if err != nil {
+		return sendConvertError(c, err)
+	}
+
+	resp := RatesResponse{
+		Base:  strings.ToUpper(base),
+		Rates: make(map[string]RateResponse, len(rates)),
+	}
+	for _, rate := range rates {
+		value, _ := rate.Value.Float64()
+		resp.Rates[string(rate.Quote)] = RateResponse{
+			Rate:      value,
+			Timestamp: newRateTimestamp(rate),
+			Stale:     rate.Stale,
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
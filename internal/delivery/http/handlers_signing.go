@@ -0,0 +1,108 @@
+package http
+
+import (
+	"blum-test/common/models"
+	"blum-test/common/signature"
+	"blum-test/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+)
+
+// SignatureResponse is the signature of the payload, the payload
+// is verified by the public key with common/signature package
+type SignatureResponse struct {
+	Algorithm string            `json:"algorithm" example:"Ed25519"`
+	KeyID     string            `json:"key_id" example:"3f2a9c0d1e4b5a67"`
+	Payload   signature.Payload `json:"payload"`
+	// Value is base64 encoded signature
+	Value string `json:"value"`
+}
+
+type PublicKeyResponse struct {
+	Algorithm string `json:"algorithm" example:"Ed25519"`
+	KeyID     string `json:"key_id" example:"3f2a9c0d1e4b5a67"`
+	// PublicKey is base64 encoded raw public key
+	PublicKey string `json:"public_key"`
+}
+
+// PublicKey
+// @Summary      Returns the public key of the responses signatures
+// @Tags         signing
+// @Produce      json
+// @Success      200  {object}  PublicKeyResponse
+// @Failure      404  {object}  ErrorResponse  "signing is not configured"
+// @Router       /v0/signing/public-key [get]
+func (s *Server) PublicKey(c *fiber.Ctx) error {
+	if s.signer == nil {
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+			Error: "signing is not configured",
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(PublicKeyResponse{
+		Algorithm: signature.Algorithm,
+		KeyID:     s.signer.KeyID(),
+		PublicKey: signature.EncodePublicKey(s.signer.PublicKey()),
+	})
+}
+
+// newConversionPayload covers the conversion by the current rates
+func newConversionPayload(amount decimal.Decimal, res *service.Conversion) signature.Payload {
+	return signature.Payload{
+		Base:      string(res.Rate.Base),
+		Quote:     string(res.Rate.Quote),
+		Side:      string(res.Side),
+		Amount:    amount.String(),
+		Rate:      res.Price.String(),
+		Gross:     res.Charge.Gross.StringFixed(res.Decimals),
+		Fee:       res.Charge.Fee.StringFixed(res.Decimals),
+		Output:    res.Output.StringFixed(res.Decimals),
+		Timestamp: time.Now(),
+	}
+}
+
+// newQuotePayload covers the locked rate of the quote and its validity
+func newQuotePayload(quote *models.Quote) signature.Payload {
+	expiresAt := quote.ExpiresAt.UTC()
+
+	return signature.Payload{
+		Base:      string(quote.Base),
+		Quote:     string(quote.Quote),
+		Side:      quote.Side,
+		Rate:      quote.Rate.String(),
+		QuoteID:   quote.ID,
+		ExpiresAt: &expiresAt,
+		Timestamp: quote.CreatedAt,
+	}
+}
+
+// newExecutionPayload covers the conversion by the locked rate of the quote
+func newExecutionPayload(amount decimal.Decimal, res *service.QuoteConversion) signature.Payload {
+	payload := newQuotePayload(&res.Quote)
+	payload.Amount = amount.String()
+	payload.Gross = res.Charge.Gross.StringFixed(res.Decimals)
+	payload.Fee = res.Charge.Fee.StringFixed(res.Decimals)
+	payload.Output = res.Output.StringFixed(res.Decimals)
+	payload.Timestamp = time.Now()
+
+	return payload
+}
+
+// sign returns nil if signing is not configured
+func (s *Server) sign(payload signature.Payload) *SignatureResponse {
+	if s.signer == nil {
+		return nil
+	}
+
+	payload.Timestamp = payload.Timestamp.UTC()
+
+	return &SignatureResponse{
+		Algorithm: signature.Algorithm,
+		KeyID:     s.signer.KeyID(),
+		Payload:   payload,
+		Value:     s.signer.Sign(payload),
+	}
+}
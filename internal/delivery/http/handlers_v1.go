@@ -0,0 +1,127 @@
+package http
+
+import (
+	"blum-test/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+)
+
+// ConvertResponseV1 keeps amounts and rates as decimal strings
+// to avoid floating point precision loss
+type ConvertResponseV1 struct {
+	// Output is the net amount after the fee
+	Output string `json:"output" example:"0.03512"`
+	Gross  string `json:"gross" example:"0.03522"`
+	Fee    string `json:"fee" example:"0.0001"`
+	// Rate is the rate of the requested side the output was converted by
+	Rate string `json:"rate" example:"0.0003512"`
+	Bid  string `json:"bid" example:"0.0003505"`
+	Ask  string `json:"ask" example:"0.0003519"`
+	// Path is the chain of currencies the cross rate was calculated through
+	Path []string `json:"path"`
+	// Stale is set if the rate is restored from the persisted snapshots
+	// because the providers are not available
+	Stale bool `json:"stale"`
+	// RateTimestamp is the fetch time of the oldest quote used,
+	// omitted for the same currency conversion
+	RateTimestamp *time.Time `json:"rate_timestamp,omitempty"`
+	// Sources are filled only if requested
+	Sources []RateSourceV1 `json:"sources,omitempty"`
+	// Signature is filled only if signing is configured
+	Signature *SignatureResponse `json:"signature,omitempty"`
+}
+
+type RateSourceV1 struct {
+	Pair      string   `json:"pair"`
+	Rate      string   `json:"rate"`
+	Providers []string `json:"providers"`
+	Spread    string   `json:"spread"`
+}
+
+// ConvertV1
+// @Summary      Converts amount of base currency to quote currency
+// @Description  Exact decimal conversion, amount and output are decimal strings
+// @Tags         rates
+// @Produce      json
+// @Param        base      query     string   true   "base currency code"             example(USD)
+// @Param        quote     query     string   true   "quote currency code"            example(ETH)
+// @Param        amount    query     string   true   "input amount of base currency"  example(100.25)
+// @Param        decimals  query     integer  false  "round to decimals places, quote currency minor units by default"  example(5)
+// @Param        rounding  query     string   false  "rounding mode of the output"    Enums(half_up, half_even, floor, ceil, truncate)  default(half_up)
+// @Param        sources   query     boolean  false  "include providers of the rates" default(false)
+// @Param        side      query     string   false  "side of the client, sell converts by bid rate, buy by ask rate"  Enums(mid, sell, buy)  default(mid)
+// @Success      200       {object}  ConvertResponseV1
+// @Failure      400       {object}  ErrorResponse  "invalid parameters or forbidden currency types pair"
+// @Failure      422       {object}  ErrorResponse  "currency or rate not exists"
+// @Failure      500
+// @Failure      503       {object}  ErrorResponse  "rate is older than max age"
+// @Router       /v1/convert [get]
+func (s *Server) ConvertV1(c *fiber.Ctx) error {
+	base := c.Query("base")
+	quote := c.Query("quote")
+	amountStr := c.Query("amount")
+
+	amountDecimal, err := decimal.NewFromString(amountStr)
+	if err == nil {
+		err = service.ValidateAmount(amountDecimal)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	params, err := parseConvertParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	res, err := s.svc.Convert(c.Context(), service.ConvertRequest{
+		Base:     base,
+		Quote:    quote,
+		Amount:   amountDecimal,
+		Decimals: params.decimals,
+		Rounding: params.rounding,
+		Side:     params.side,
+	})
+	if err != nil {
+		return sendConvertError(c, err)
+	}
+
+	resp := ConvertResponseV1{
+		Output:        res.Output.StringFixed(res.Decimals),
+		Gross:         res.Charge.Gross.StringFixed(res.Decimals),
+		Fee:           res.Charge.Fee.StringFixed(res.Decimals),
+		Rate:          res.Price.String(),
+		Bid:           res.Bid.String(),
+		Ask:           res.Ask.String(),
+		Path:          newPath(res.Rate, res.Path),
+		Stale:         res.Rate.Stale,
+		RateTimestamp: newRateTimestamp(res.Rate),
+	}
+
+	if params.sources {
+		resp.Sources = make([]RateSourceV1, 0, len(res.Path))
+		for _, rate := range res.Path {
+			resp.Sources = append(resp.Sources, newRateSourceV1(rate))
+		}
+	}
+
+	resp.Signature = s.sign(newConversionPayload(amountDecimal, res))
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func newRateSourceV1(rate service.Rate) RateSourceV1 {
+	return RateSourceV1{
+		Pair:      rate.Pair(),
+		Rate:      rate.Value.String(),
+		Providers: rate.Providers,
+		Spread:    rate.Spread.String(),
+	}
+}
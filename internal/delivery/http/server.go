@@ -3,6 +3,7 @@ package http
 import (
 	"blum-test/common/config"
 	"blum-test/common/logger"
+	"blum-test/common/signature"
 	"blum-test/internal/service"
 	"context"
 	"errors"
@@ -24,9 +25,11 @@ type Server struct {
 	cfg *config.AppConfig
 	app *fiber.App
 	svc *service.RateCalculator
+	// signer is nil if signing is not configured
+	signer *signature.Signer
 }
 
-func NewServer(cfg *config.AppConfig, svc *service.RateCalculator) *Server {
+func NewServer(cfg *config.AppConfig, svc *service.RateCalculator, signer *signature.Signer) *Server {
 	app := fiber.New(fiber.Config{
 		CaseSensitive: true,
 		JSONEncoder:   json.Marshal,
@@ -34,9 +37,10 @@ func NewServer(cfg *config.AppConfig, svc *service.RateCalculator) *Server {
 	app.Use(mlogger.New())
 
 	return &Server{
-		cfg: cfg,
-		app: app,
-		svc: svc,
+		cfg:    cfg,
+		app:    app,
+		svc:    svc,
+		signer: signer,
 	}
 }
 
@@ -47,11 +51,30 @@ func (s *Server) Start() error {
 
 	host := fmt.Sprintf("%s:%d", s.cfg.HTTPServer.Host, s.cfg.HTTPServer.Port)
 	SwaggerInfo.Host = host
-	SwaggerInfo.BasePath = "/v0"
+	SwaggerInfo.BasePath = "/"
 	s.app.Get("/swagger/*", fiberSwagger.FiberWrapHandler())
 
 	api := s.app.Group("/v0")
 	api.Get("/convert", s.Convert)
+	api.Get("/convert/historical", s.ConvertHistorical)
+	api.Post("/convert/batch", s.ConvertBatch)
+	api.Get("/currencies", s.ListCurrencies)
+	api.Get("/rates", s.Rates)
+	api.Get("/signing/public-key", s.PublicKey)
+	api.Post("/quotes", s.CreateQuote)
+	api.Post("/quotes/:id/execute", s.ExecuteQuote)
+
+	if s.cfg.HTTPServer.AdminToken != "" {
+		admin := api.Group("/admin", s.adminAuth)
+		admin.Post("/currencies", s.CreateCurrency)
+		admin.Patch("/currencies/:code", s.UpdateCurrency)
+		admin.Delete("/currencies/:code", s.DeleteCurrency)
+	} else {
+		logger.JSONLogger.Warn("admin token is not set, admin API is disabled")
+	}
+
+	apiV1 := s.app.Group("/v1")
+	apiV1.Get("/convert", s.ConvertV1)
 
 	go func() {
 		if err := s.app.Listen(host); err != nil && !errors.Is(err, http.ErrServerClosed) {
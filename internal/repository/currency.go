@@ -27,7 +27,7 @@ func NewCurrencyPostgresRepository(client *pgxpool.Pool) ICurrencyRepository {
 
 func (r *repo) ListEnabledCurrencies(ctx context.Context) ([]models.Currency, error) {
 	query := `
-		SELECT c.name, c.code, t.name, c.is_enabled, c.updated_at
+		SELECT c.name, c.code, t.name, c.minor_units, c.is_enabled, c.updated_at
 		FROM currencies c LEFT JOIN currency_types t ON c.type_id = t.id
 		WHERE c.is_enabled = true;
 	`
@@ -45,6 +45,7 @@ func (r *repo) ListEnabledCurrencies(ctx context.Context) ([]models.Currency, er
 			&currency.Name,
 			&currency.Code,
 			&currency.Type,
+			&currency.MinorUnits,
 			&currency.IsEnabled,
 			&currency.UpdatedAt,
 		); err != nil {
@@ -57,6 +58,125 @@ func (r *repo) ListEnabledCurrencies(ctx context.Context) ([]models.Currency, er
 	return res, nil
 }
 
+func (r *repo) CreateCurrency(ctx context.Context, currency models.Currency, isEnabled *bool) error {
+	query := `
+		INSERT INTO currencies (name, code, type_id, minor_units)
+		SELECT $1, $2, t.id, $4
+		FROM currency_types t
+		WHERE t.name = $3;
+	`
+	args := []any{
+		currency.Name,
+		string(currency.Code),
+		string(currency.Type),
+		currency.MinorUnits,
+	}
+	if isEnabled != nil {
+		query = `
+			INSERT INTO currencies (name, code, type_id, minor_units, is_enabled)
+			SELECT $1, $2, t.id, $4, $5
+			FROM currency_types t
+			WHERE t.name = $3;
+		`
+		args = append(args, *isEnabled)
+	}
+
+	tag, err := r.client.Exec(ctx, query, args...)
+	if err != nil {
+		if db.CheckErrUniqueViolation(err) {
+			return &ErrCurrencyExists{
+				Code: currency.Code,
+			}
+		}
+		return fmt.Errorf("error while inserting currency: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return &ErrUnknownCurrencyType{
+			Type: currency.Type,
+		}
+	}
+
+	return nil
+}
+
+func (r *repo) UpdateCurrency(ctx context.Context, code models.CurrencyCode, update CurrencyUpdate) error {
+	// empty update is not executed, so no event is sent for it
+	if update.IsEnabled == nil && update.Type == nil {
+		return r.checkCurrencyExists(ctx, code)
+	}
+
+	// single statement, so the fields are changed atomically,
+	// unknown type leaves no row to update
+	query := `
+		UPDATE currencies c SET
+			is_enabled = COALESCE($2::boolean, c.is_enabled),
+			type_id = COALESCE(t.id, c.type_id)
+		FROM (SELECT 1) AS one
+		LEFT JOIN currency_types t ON t.name = $3::text
+		WHERE c.code = $1 AND ($3::text IS NULL OR t.id IS NOT NULL);
+	`
+
+	var currencyType *string
+	if update.Type != nil {
+		value := string(*update.Type)
+		currencyType = &value
+	}
+
+	tag, err := r.client.Exec(ctx, query, string(code), update.IsEnabled, currencyType)
+	if err != nil {
+		return fmt.Errorf("error while updating currency: %w", err)
+	}
+
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	// nothing is updated either for unknown currency or for unknown type
+	if err := r.checkCurrencyExists(ctx, code); err != nil || update.Type == nil {
+		return err
+	}
+
+	return &ErrUnknownCurrencyType{
+		Type: *update.Type,
+	}
+}
+
+// checkCurrencyExists returns ErrCurrencyNotFound for unknown currency
+func (r *repo) checkCurrencyExists(ctx context.Context, code models.CurrencyCode) error {
+	var exists bool
+	if err := r.client.QueryRow(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM currencies WHERE code = $1);`,
+		string(code),
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("error while quering db: %w", err)
+	}
+
+	if !exists {
+		return ErrCurrencyNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) DeleteCurrency(ctx context.Context, code models.CurrencyCode) error {
+	query := `
+		DELETE FROM currencies WHERE code = $1;
+	`
+
+	tag, err := r.client.Exec(ctx, query, string(code))
+	if err != nil {
+		return fmt.Errorf("error while deleting currency: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrCurrencyNotFound
+	}
+
+	return nil
+}
+
 func (r *repo) updateCurrencyTypeMap(ctx context.Context) error {
 	query := `
 		SELECT id, name FROM currency_types;
@@ -84,58 +204,97 @@ func (r *repo) updateCurrencyTypeMap(ctx context.Context) error {
 	return nil
 }
 
+const currencyEventsChannel = "currency_events"
+
+// SubscribeToCurrencyUpdates listens to the currency events. Lost connection
+// is restored with backoff and followed by OperationResync notification, since
+// events could be missed meanwhile. The channel is closed when the context is
+// done or the connection could not be restored.
 func (r *repo) SubscribeToCurrencyUpdates(ctx context.Context) (<-chan CurrencyNotification, error) {
 	if err := r.updateCurrencyTypeMap(ctx); err != nil {
 		return nil, fmt.Errorf("could not update currency types: %w", err)
 	}
 
-	conn, err := r.client.Acquire(ctx)
+	conn, err := listen(ctx, r.client, currencyEventsChannel)
 	if err != nil {
-		return nil, fmt.Errorf("could not acquire conn: %w", err)
+		return nil, err
 	}
 
 	res := make(chan CurrencyNotification, 10)
 
-	_, err = conn.Exec(ctx, "LISTEN currency_events")
-	if err != nil {
-		return nil, fmt.Errorf("could not LISTEN to currency events: %w", err)
-	}
-
 	go func() {
-		defer conn.Release()
+		defer close(res)
 		for {
-			notification, err := conn.Conn().WaitForNotification(ctx)
-			if err != nil {
-				logger.JSONLogger.Error(
-					"error waiting notification: %w",
-					err,
-				)
-				return
-			}
+			err := r.waitNotifications(ctx, conn, res)
+			closeListen(conn)
 
-			if err := r.updateCurrencyTypeMap(ctx); err != nil {
-				logger.JSONLogger.Error(
-					"could not update currency types: %w",
-					err,
-				)
+			if ctx.Err() != nil {
 				return
 			}
 
-			payload := CurrencyNotification{}
+			logger.JSONLogger.Error(
+				"error waiting notification, reconnecting",
+				slog.Any("error", err),
+			)
 
-			if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			conn, err = reconnect(ctx, r.client, currencyEventsChannel)
+			if err != nil {
 				logger.JSONLogger.Error(
-					"unexpected event in the channel",
-					slog.Any("payload", notification.Payload),
+					"could not restore currency events subscription",
 					slog.Any("error", err),
 				)
+				return
 			}
 
-			payload.Currency.CurrencyType = r.currencyTypes[payload.Currency.TypeId]
-
-			res <- payload
+			select {
+			case res <- CurrencyNotification{Operation: OperationResync}:
+			case <-ctx.Done():
+				closeListen(conn)
+				return
+			}
 		}
 	}()
 
 	return res, nil
 }
+
+// waitNotifications sends the notifications to the channel
+// until the connection error or the context is done
+func (r *repo) waitNotifications(
+	ctx context.Context,
+	conn *pgxpool.Conn,
+	res chan<- CurrencyNotification,
+) error {
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := r.updateCurrencyTypeMap(ctx); err != nil {
+			logger.JSONLogger.Error(
+				"could not update currency types",
+				slog.Any("error", err),
+			)
+		}
+
+		payload := CurrencyNotification{}
+
+		if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			logger.JSONLogger.Error(
+				"unexpected event in the channel",
+				slog.Any("payload", notification.Payload),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		payload.Currency.CurrencyType = r.currencyTypes[payload.Currency.TypeId]
+
+		select {
+		case res <- payload:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
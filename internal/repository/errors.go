@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"blum-test/common/models"
+	"errors"
+	"fmt"
+)
+
+var ErrCurrencyNotFound = errors.New("currency not found")
+var ErrQuoteNotFound = errors.New("quote not found")
+
+type ErrCurrencyExists struct {
+	Code models.CurrencyCode
+}
+
+func (e *ErrCurrencyExists) Error() string {
+	return fmt.Sprintf("currency \"%s\" already exists", e.Code)
+}
+
+type ErrUnknownCurrencyType struct {
+	Type models.CurrencyType
+}
+
+func (e *ErrUnknownCurrencyType) Error() string {
+	return fmt.Sprintf("unknown currency type \"%s\"", e.Type)
+}
+
+type ErrSubscriptionLost struct {
+	Attempts int
+	Err      error
+}
+
+func (e *ErrSubscriptionLost) Error() string {
+	return fmt.Sprintf("subscription lost after %d reconnect attempts: %s", e.Attempts, e.Err)
+}
+
+func (e *ErrSubscriptionLost) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"blum-test/common/logger"
+	"blum-test/common/models"
+	"blum-test/internal/db"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+const feeRuleEventsChannel = "fee_rule_events"
+
+type feeRepo struct {
+	client *pgxpool.Pool
+}
+
+func NewFeePostgresRepository(client *pgxpool.Pool) IFeeRepository {
+	return &feeRepo{
+		client: client,
+	}
+}
+
+func (r *feeRepo) ListFeeRules(ctx context.Context) ([]models.FeeRule, error) {
+	query := `
+		SELECT scope, target, min_amount::text, flat::text, bps, min_fee::text, max_fee::text
+		FROM fee_rules
+		ORDER BY scope, target, min_amount;
+	`
+
+	rows, err := r.client.Query(ctx, query)
+	if err != nil && !db.CheckErrNoRows(err) {
+		return nil, fmt.Errorf("error while quering db: %w", err)
+	}
+	defer rows.Close()
+
+	res := []models.FeeRule{}
+	for rows.Next() {
+		rule := models.FeeRule{}
+		var minAmount, flat, minFee string
+		var maxFee *string
+		if err := rows.Scan(
+			&rule.Scope,
+			&rule.Target,
+			&minAmount,
+			&flat,
+			&rule.Bps,
+			&minFee,
+			&maxFee,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning values: %w", err)
+		}
+
+		if rule.MinAmount, err = decimal.NewFromString(minAmount); err != nil {
+			return nil, fmt.Errorf("invalid min amount of fee rule %s: %w", rule.Target, err)
+		}
+		if rule.Flat, err = decimal.NewFromString(flat); err != nil {
+			return nil, fmt.Errorf("invalid flat fee of fee rule %s: %w", rule.Target, err)
+		}
+		if rule.MinFee, err = decimal.NewFromString(minFee); err != nil {
+			return nil, fmt.Errorf("invalid min fee of fee rule %s: %w", rule.Target, err)
+		}
+		if maxFee != nil {
+			value, err := decimal.NewFromString(*maxFee)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max fee of fee rule %s: %w", rule.Target, err)
+			}
+			rule.MaxFee = &value
+		}
+
+		res = append(res, rule)
+	}
+
+	return res, nil
+}
+
+// SubscribeToFeeRuleUpdates listens to the fee rule events, reconnection
+// works the same way as for SubscribeToCurrencyUpdates
+func (r *feeRepo) SubscribeToFeeRuleUpdates(ctx context.Context) (<-chan FeeRuleNotification, error) {
+	conn, err := listen(ctx, r.client, feeRuleEventsChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(chan FeeRuleNotification, 10)
+
+	go func() {
+		defer close(res)
+		for {
+			err := r.waitNotifications(ctx, conn, res)
+			closeListen(conn)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.JSONLogger.Error(
+				"error waiting fee rule notification, reconnecting",
+				slog.Any("error", err),
+			)
+
+			conn, err = reconnect(ctx, r.client, feeRuleEventsChannel)
+			if err != nil {
+				logger.JSONLogger.Error(
+					"could not restore fee rule events subscription",
+					slog.Any("error", err),
+				)
+				return
+			}
+
+			select {
+			case res <- FeeRuleNotification{Operation: OperationResync}:
+			case <-ctx.Done():
+				closeListen(conn)
+				return
+			}
+		}
+	}()
+
+	return res, nil
+}
+
+func (r *feeRepo) waitNotifications(
+	ctx context.Context,
+	conn *pgxpool.Conn,
+	res chan<- FeeRuleNotification,
+) error {
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		payload := FeeRuleNotification{}
+
+		if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			logger.JSONLogger.Error(
+				"unexpected event in the channel",
+				slog.Any("payload", notification.Payload),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		select {
+		case res <- payload:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -3,11 +3,64 @@ package repository
 import (
 	"blum-test/common/models"
 	"context"
+	"time"
 )
 
 type ICurrencyRepository interface {
 	ListEnabledCurrencies(ctx context.Context) ([]models.Currency, error)
 	SubscribeToCurrencyUpdates(ctx context.Context) (<-chan CurrencyNotification, error)
+
+	// write methods changes are propagated to the subscribers
+	// by the currency_events trigger
+
+	// CreateCurrency stores the currency, is_enabled column default
+	// is used if isEnabled is nil
+	CreateCurrency(ctx context.Context, currency models.Currency, isEnabled *bool) error
+	// UpdateCurrency changes the set fields of the update at once
+	UpdateCurrency(ctx context.Context, code models.CurrencyCode, update CurrencyUpdate) error
+	DeleteCurrency(ctx context.Context, code models.CurrencyCode) error
+}
+
+type IFeeRepository interface {
+	ListFeeRules(ctx context.Context) ([]models.FeeRule, error)
+	// SubscribeToFeeRuleUpdates notifies about any change of the fee rules
+	// by the fee_rule_events trigger, the rules should be reloaded
+	SubscribeToFeeRuleUpdates(ctx context.Context) (<-chan FeeRuleNotification, error)
+}
+
+type IMarkupRepository interface {
+	ListMarkups(ctx context.Context) ([]models.Markup, error)
+}
+
+type IQuoteRepository interface {
+	// CreateQuote stores the quote and returns it with generated ID
+	CreateQuote(ctx context.Context, quote models.Quote) (*models.Quote, error)
+	GetQuote(ctx context.Context, id string) (*models.Quote, error)
+}
+
+type IRateRepository interface {
+	SaveRateSnapshots(ctx context.Context, snapshots []models.RateSnapshot) error
+	// GetRateSnapshotsAt returns the latest snapshots of the currencies
+	// fetched not after the moment, currencies without snapshots are omitted
+	GetRateSnapshotsAt(
+		ctx context.Context,
+		codes []models.CurrencyCode,
+		at time.Time,
+	) (map[models.CurrencyCode]models.RateSnapshot, error)
+}
+
+// OperationResync is sent after the lost subscription is restored,
+// currencies or fee rules should be reloaded since events could be missed
+const OperationResync = "RESYNC"
+
+// CurrencyUpdate is the currency fields to change, nil fields are kept
+type CurrencyUpdate struct {
+	IsEnabled *bool
+	Type      *models.CurrencyType
+}
+
+type FeeRuleNotification struct {
+	Operation string `json:"operation"`
 }
 
 type CurrencyNotification struct {
@@ -16,6 +69,7 @@ type CurrencyNotification struct {
 		Name         string              `json:"name"`
 		Code         string              `json:"code"`
 		IsEnabled    bool                `json:"is_enabled"`
+		MinorUnits   int32               `json:"minor_units"`
 		TypeId       int                 `json:"type_id"`
 		CurrencyType models.CurrencyType `json:"-"`
 	} `json:"currency"`
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"blum-test/common/logger"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	reconnectMinBackoff  = time.Second
+	reconnectMaxBackoff  = time.Minute
+	maxReconnectAttempts = 10
+)
+
+func listen(ctx context.Context, client *pgxpool.Pool, channel string) (*pgxpool.Conn, error) {
+	conn, err := client.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not acquire conn: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, "LISTEN "+channel)
+	if err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("could not LISTEN to %s: %w", channel, err)
+	}
+
+	return conn, nil
+}
+
+// reconnect retries to LISTEN with exponential backoff
+func reconnect(ctx context.Context, client *pgxpool.Pool, channel string) (*pgxpool.Conn, error) {
+	backoff := reconnectMinBackoff
+
+	var err error
+	for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		var conn *pgxpool.Conn
+		conn, err = listen(ctx, client, channel)
+		if err == nil {
+			logger.JSONLogger.Info(
+				"subscription restored",
+				slog.String("channel", channel),
+				slog.Int("attempt", attempt),
+			)
+			return conn, nil
+		}
+
+		logger.JSONLogger.Warn(
+			"could not reconnect to channel",
+			slog.String("channel", channel),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err),
+		)
+
+		backoff = min(backoff*2, reconnectMaxBackoff)
+	}
+
+	return nil, &ErrSubscriptionLost{
+		Attempts: maxReconnectAttempts,
+		Err:      err,
+	}
+}
+
+// closeListen closes the connection in LISTEN state,
+// it should not be returned to the pool
+func closeListen(conn *pgxpool.Conn) {
+	conn.Conn().Close(context.Background())
+	conn.Release()
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"blum-test/common/models"
+	"blum-test/internal/db"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type markupRepo struct {
+	client *pgxpool.Pool
+}
+
+func NewMarkupPostgresRepository(client *pgxpool.Pool) IMarkupRepository {
+	return &markupRepo{
+		client: client,
+	}
+}
+
+func (r *markupRepo) ListMarkups(ctx context.Context) ([]models.Markup, error) {
+	query := `
+		SELECT scope, target, bps FROM markups;
+	`
+
+	rows, err := r.client.Query(ctx, query)
+	if err != nil && !db.CheckErrNoRows(err) {
+		return nil, fmt.Errorf("error while quering db: %w", err)
+	}
+	defer rows.Close()
+
+	res := []models.Markup{}
+	for rows.Next() {
+		markup := models.Markup{}
+		if err := rows.Scan(
+			&markup.Scope,
+			&markup.Target,
+			&markup.Bps,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning values: %w", err)
+		}
+
+		res = append(res, markup)
+	}
+
+	return res, nil
+}
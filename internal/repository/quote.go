@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"blum-test/common/models"
+	"blum-test/internal/db"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type quoteRepo struct {
+	client *pgxpool.Pool
+}
+
+func NewQuotePostgresRepository(client *pgxpool.Pool) IQuoteRepository {
+	return &quoteRepo{
+		client: client,
+	}
+}
+
+func (r *quoteRepo) CreateQuote(ctx context.Context, quote models.Quote) (*models.Quote, error) {
+	query := `
+		INSERT INTO quotes (base_code, quote_code, side, rate, fees, created_at, expires_at)
+		VALUES ($1, $2, $3, $4::numeric, $5::jsonb, $6, $7)
+		RETURNING id::text;
+	`
+
+	if quote.Fees == nil {
+		quote.Fees = []models.FeeSchedule{}
+	}
+
+	fees, err := json.Marshal(quote.Fees)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal quote fees: %w", err)
+	}
+
+	if err := r.client.QueryRow(
+		ctx,
+		query,
+		string(quote.Base),
+		string(quote.Quote),
+		quote.Side,
+		quote.Rate.String(),
+		string(fees),
+		quote.CreatedAt.UTC(),
+		quote.ExpiresAt.UTC(),
+	).Scan(&quote.ID); err != nil {
+		return nil, fmt.Errorf("error while inserting quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+func (r *quoteRepo) GetQuote(ctx context.Context, id string) (*models.Quote, error) {
+	query := `
+		SELECT id::text, base_code, quote_code, side, rate::text, fees::text, created_at, expires_at
+		FROM quotes
+		WHERE id = $1::uuid;
+	`
+
+	quote := models.Quote{}
+	var rate, fees string
+	if err := r.client.QueryRow(ctx, query, id).Scan(
+		&quote.ID,
+		&quote.Base,
+		&quote.Quote,
+		&quote.Side,
+		&rate,
+		&fees,
+		&quote.CreatedAt,
+		&quote.ExpiresAt,
+	); err != nil {
+		if db.CheckErrNoRows(err) {
+			return nil, ErrQuoteNotFound
+		}
+		return nil, fmt.Errorf("error while quering db: %w", err)
+	}
+
+	value, err := decimal.NewFromString(rate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate of quote %s: %w", id, err)
+	}
+	quote.Rate = value
+
+	if err := json.Unmarshal([]byte(fees), &quote.Fees); err != nil {
+		return nil, fmt.Errorf("invalid fees of quote %s: %w", id, err)
+	}
+
+	return &quote, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"blum-test/common/models"
+	"blum-test/internal/db"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type rateRepo struct {
+	client *pgxpool.Pool
+}
+
+func NewRatePostgresRepository(client *pgxpool.Pool) IRateRepository {
+	return &rateRepo{
+		client: client,
+	}
+}
+
+func (r *rateRepo) SaveRateSnapshots(ctx context.Context, snapshots []models.RateSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO rate_snapshots (currency_code, rate_in_usd, provider, fetched_at)
+		VALUES ($1, $2, $3, $4);
+	`
+
+	batch := &pgx.Batch{}
+	for _, snapshot := range snapshots {
+		batch.Queue(
+			query,
+			snapshot.Code,
+			snapshot.RateInUSD.String(),
+			snapshot.Provider,
+			snapshot.FetchedAt.UTC(),
+		)
+	}
+
+	results := r.client.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range snapshots {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("error while inserting rate snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *rateRepo) GetRateSnapshotsAt(
+	ctx context.Context,
+	codes []models.CurrencyCode,
+	at time.Time,
+) (map[models.CurrencyCode]models.RateSnapshot, error) {
+	query := `
+		SELECT DISTINCT ON (currency_code) currency_code, rate_in_usd::text, provider, fetched_at
+		FROM rate_snapshots
+		WHERE currency_code = ANY($1) AND fetched_at <= $2
+		ORDER BY currency_code, fetched_at DESC;
+	`
+
+	codesParam := make([]string, 0, len(codes))
+	for _, code := range codes {
+		codesParam = append(codesParam, string(code))
+	}
+
+	rows, err := r.client.Query(ctx, query, codesParam, at.UTC())
+	if err != nil && !db.CheckErrNoRows(err) {
+		return nil, fmt.Errorf("error while quering db: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRateSnapshots(rows)
+}
+
+func scanRateSnapshots(rows pgx.Rows) (map[models.CurrencyCode]models.RateSnapshot, error) {
+	res := make(map[models.CurrencyCode]models.RateSnapshot)
+	for rows.Next() {
+		snapshot := models.RateSnapshot{}
+		var rate string
+		if err := rows.Scan(
+			&snapshot.Code,
+			&rate,
+			&snapshot.Provider,
+			&snapshot.FetchedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning values: %w", err)
+		}
+
+		rateInUSD, err := decimal.NewFromString(rate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate of %s: %w", snapshot.Code, err)
+		}
+		snapshot.RateInUSD = rateInUSD
+
+		res[snapshot.Code] = snapshot
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error while reading rows: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"blum-test/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxCurrencyCodeLength is the length of currencies.code column
+const maxCurrencyCodeLength = 10
+
+// CreateCurrency stores the new currency, the service cache is
+// updated by the currency events as for the other instances. The
+// currency is enabled by the column default if isEnabled is nil
+func (c *RateCalculator) CreateCurrency(ctx context.Context, currency Currency, isEnabled *bool) error {
+	code, err := parseCurrencyCode(string(currency.Code))
+	if err != nil {
+		return err
+	}
+	currency.Code = code
+	currency.Type = CurrencyType(strings.ToUpper(string(currency.Type)))
+
+	if strings.TrimSpace(currency.Name) == "" {
+		return &ErrInvalidCurrency{
+			Field:  "name",
+			Reason: "should not be empty",
+		}
+	}
+	// minor units are the default decimals of the conversions,
+	// so they are limited the same way
+	if maxDecimals := c.maxDecimals(&currency); currency.MinorUnits < 0 || currency.MinorUnits > maxDecimals {
+		return &ErrInvalidCurrency{
+			Field:  "minor_units",
+			Reason: fmt.Sprintf("should be from 0 to %d", maxDecimals),
+		}
+	}
+
+	if err := c.repo.CreateCurrency(ctx, currency, isEnabled); err != nil {
+		return fmt.Errorf("CreateCurrency(): %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCurrency changes the set fields of the currency at once,
+// nil fields are kept, the empty update only checks the currency exists
+func (c *RateCalculator) UpdateCurrency(
+	ctx context.Context,
+	code string,
+	isEnabled *bool,
+	currencyType *string,
+) error {
+	currencyCode, err := parseCurrencyCode(code)
+	if err != nil {
+		return err
+	}
+
+	update := repository.CurrencyUpdate{
+		IsEnabled: isEnabled,
+	}
+	if currencyType != nil {
+		value := CurrencyType(strings.ToUpper(*currencyType))
+		update.Type = &value
+	}
+
+	if err := c.repo.UpdateCurrency(ctx, currencyCode, update); err != nil {
+		return fmt.Errorf("UpdateCurrency(): %w", err)
+	}
+
+	return nil
+}
+
+func (c *RateCalculator) DeleteCurrency(ctx context.Context, code string) error {
+	currencyCode, err := parseCurrencyCode(code)
+	if err != nil {
+		return err
+	}
+
+	if err := c.repo.DeleteCurrency(ctx, currencyCode); err != nil {
+		return fmt.Errorf("DeleteCurrency(): %w", err)
+	}
+
+	return nil
+}
+
+func parseCurrencyCode(code string) (CurrencyCode, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" || len(code) > maxCurrencyCodeLength {
+		return "", &ErrInvalidCurrency{
+			Field:  "code",
+			Reason: fmt.Sprintf("should be from 1 to %d characters", maxCurrencyCodeLength),
+		}
+	}
+
+	return CurrencyCode(code), nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"blum-test/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeAdminRepo records the writes of the currencies
+type fakeAdminRepo struct {
+	repository.ICurrencyRepository
+
+	created   *Currency
+	isEnabled *bool
+	updates   []repository.CurrencyUpdate
+	known     map[CurrencyCode]bool
+}
+
+func (r *fakeAdminRepo) CreateCurrency(ctx context.Context, currency Currency, isEnabled *bool) error {
+	r.created = &currency
+	r.isEnabled = isEnabled
+	return nil
+}
+
+func (r *fakeAdminRepo) UpdateCurrency(ctx context.Context, code CurrencyCode, update repository.CurrencyUpdate) error {
+	r.updates = append(r.updates, update)
+	if !r.known[code] {
+		return repository.ErrCurrencyNotFound
+	}
+	return nil
+}
+
+func TestCreateCurrency(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name      string
+		currency  Currency
+		isEnabled *bool
+		wantErr   bool
+	}{
+		{name: "enabled by default", currency: Currency{Code: " btc ", Name: "Bitcoin", Type: "crypto", MinorUnits: 8}},
+		{name: "disabled", currency: Currency{Code: "BTC", Name: "Bitcoin", Type: Crypto, MinorUnits: 8}, isEnabled: &disabled},
+		{name: "empty code", currency: Currency{Code: " ", Name: "Bitcoin", Type: Crypto}, wantErr: true},
+		{name: "long code", currency: Currency{Code: "BITCOINCASH", Name: "Bitcoin", Type: Crypto}, wantErr: true},
+		{name: "empty name", currency: Currency{Code: "BTC", Type: Crypto}, wantErr: true},
+		{name: "negative minor units", currency: Currency{Code: "BTC", Name: "Bitcoin", Type: Crypto, MinorUnits: -1}, wantErr: true},
+		{name: "too many minor units", currency: Currency{Code: "BTC", Name: "Bitcoin", Type: Crypto, MinorUnits: 19}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAdminRepo{}
+			c := &RateCalculator{repo: repo}
+
+			err := c.CreateCurrency(context.Background(), tt.currency, tt.isEnabled)
+
+			var invalidCurrency *ErrInvalidCurrency
+			if tt.wantErr != errors.As(err, &invalidCurrency) {
+				t.Fatalf("CreateCurrency() = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if repo.created != nil {
+					t.Error("invalid currency is stored")
+				}
+				return
+			}
+
+			if repo.created.Code != "BTC" || repo.created.Type != Crypto {
+				t.Errorf("stored currency = %s/%s, want normalized BTC/CRYPTO", repo.created.Code, repo.created.Type)
+			}
+			// nil keeps the column default
+			if repo.isEnabled != tt.isEnabled {
+				t.Errorf("isEnabled = %v, want %v", repo.isEnabled, tt.isEnabled)
+			}
+		})
+	}
+}
+
+func TestEmptyUpdateCurrency(t *testing.T) {
+	repo := &fakeAdminRepo{known: map[CurrencyCode]bool{"BTC": true}}
+	c := &RateCalculator{repo: repo}
+
+	if err := c.UpdateCurrency(context.Background(), "btc", nil, nil); err != nil {
+		t.Errorf("UpdateCurrency() of known currency = %v, want nil", err)
+	}
+	if err := c.UpdateCurrency(context.Background(), "XYZ", nil, nil); !errors.Is(err, repository.ErrCurrencyNotFound) {
+		t.Errorf("UpdateCurrency() of unknown currency = %v, want ErrCurrencyNotFound", err)
+	}
+	if len(repo.updates) != 2 {
+		t.Errorf("updates = %d, want the empty updates passed to the repository", len(repo.updates))
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AggregationFailover    = "failover"
+	AggregationMedian      = "median"
+	AggregationTrimmedMean = "trimmed_mean"
+)
+
+type providerRate struct {
+	provider string
+	rate     Rate
+	value    decimal.Decimal
+}
+
+// fetchAggregatedRates queries all the providers concurrently and
+// combines their quotes per currency, so a single bad feed could not
+// affect the conversion. Rates deviating from the median more than
+// RateMaxDeviation are dropped as outliers.
+func (c *RateCalculator) fetchAggregatedRates(
+	ctx context.Context,
+	currencies map[CurrencyCode]Currency,
+) (map[CurrencyCode]Rate, error) {
+	pending := make(map[CurrencyCode]Currency, len(currencies))
+	for code, currency := range currencies {
+		if c.anchorOf(currency) != code {
+			pending[code] = currency
+		}
+	}
+
+	results := make([]map[CurrencyCode]Rate, len(c.providers))
+	errs := make([]error, len(c.providers))
+
+	wg := sync.WaitGroup{}
+	for i, provider := range c.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			providerRates, err := c.fetchProviderRates(ctx, provider, pending)
+			if err != nil {
+				log.Warn(
+					"could not fetch rates from provider",
+					slog.String("provider", provider.Name()),
+					slog.Any("error", err),
+				)
+			}
+			results[i] = providerRates
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	samples := make(map[CurrencyCode][]providerRate, len(pending))
+	for i, providerRates := range results {
+		for code, rate := range providerRates {
+			if _, ok := pending[code]; !ok {
+				continue
+			}
+			samples[code] = append(samples[code], providerRate{
+				provider: c.providers[i].Name(),
+				rate:     rate,
+				value:    rate.Value,
+			})
+		}
+	}
+
+	rates := make(map[CurrencyCode]Rate, len(samples))
+	for code, codeSamples := range samples {
+		if rate, ok := c.aggregateRate(code, codeSamples); ok {
+			rates[code] = rate
+		}
+	}
+
+	if len(rates) < len(pending) {
+		codes := []CurrencyCode{}
+		for code := range pending {
+			if _, ok := rates[code]; !ok {
+				codes = append(codes, code)
+			}
+		}
+
+		return rates, &ErrRatesNotFetched{
+			Codes: codes,
+			Err:   errors.Join(errs...),
+		}
+	}
+
+	return rates, nil
+}
+
+// aggregateRate combines providers rates of the currency, false is
+// returned when all the rates were dropped as outliers
+func (c *RateCalculator) aggregateRate(code CurrencyCode, samples []providerRate) (Rate, bool) {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].value.LessThan(samples[j].value)
+	})
+
+	median := medianRate(samples)
+	maxDeviation := decimal.NewFromFloat(c.RateMaxDeviation)
+
+	contributed := make([]providerRate, 0, len(samples))
+	for _, sample := range samples {
+		if !median.IsZero() &&
+			sample.value.Sub(median).Abs().Div(median).GreaterThan(maxDeviation) {
+			log.Warn(
+				"provider rate dropped as outlier",
+				slog.String("currency_code", string(code)),
+				slog.String("provider", sample.provider),
+				slog.String("rate", sample.value.String()),
+				slog.String("median", median.String()),
+			)
+			continue
+		}
+		contributed = append(contributed, sample)
+	}
+
+	if len(contributed) == 0 {
+		log.Error(
+			"providers rates diverge, rate is not updated",
+			slog.String("currency_code", string(code)),
+			slog.String("median", median.String()),
+		)
+		return Rate{}, false
+	}
+
+	var value decimal.Decimal
+	switch c.RateAggregation {
+	case AggregationTrimmedMean:
+		value = trimmedMeanRate(contributed, c.RateTrimRatio)
+	default:
+		value = medianRate(contributed)
+	}
+
+	rate := Rate{
+		Base:      contributed[0].rate.Base,
+		Quote:     contributed[0].rate.Quote,
+		Value:     value,
+		Providers: make([]string, 0, len(contributed)),
+		Spread:    decimal.Zero,
+	}
+	for _, sample := range contributed {
+		rate.Providers = append(rate.Providers, sample.provider)
+		if rate.FetchedAt.IsZero() || sample.rate.FetchedAt.Before(rate.FetchedAt) {
+			rate.FetchedAt = sample.rate.FetchedAt
+		}
+	}
+	if !value.IsZero() {
+		rate.Spread = contributed[len(contributed)-1].value.
+			Sub(contributed[0].value).
+			Div(value)
+	}
+
+	return rate, true
+}
+
+// medianRate returns median of the rates sorted in ascending order
+func medianRate(samples []providerRate) decimal.Decimal {
+	n := len(samples)
+	if n%2 == 1 {
+		return samples[n/2].value
+	}
+
+	return samples[n/2-1].value.Add(samples[n/2].value).Div(decimal.NewFromInt(2))
+}
+
+// trimmedMeanRate returns mean of the rates sorted in ascending order
+// without trimRatio part of the lowest and the highest rates
+func trimmedMeanRate(samples []providerRate, trimRatio float64) decimal.Decimal {
+	trim := int(float64(len(samples)) * trimRatio)
+	if 2*trim >= len(samples) {
+		trim = (len(samples) - 1) / 2
+	}
+
+	sum := decimal.Zero
+	for _, sample := range samples[trim : len(samples)-trim] {
+		sum = sum.Add(sample.value)
+	}
+
+	return sum.Div(decimal.NewFromInt(int64(len(samples) - 2*trim)))
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"blum-test/common/config"
+	. "blum-test/common/models"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func newSamples(values ...string) []providerRate {
+	samples := make([]providerRate, 0, len(values))
+	for i, value := range values {
+		samples = append(samples, providerRate{
+			provider: string(rune('a' + i)),
+			value:    decimal.RequireFromString(value),
+		})
+	}
+
+	return samples
+}
+
+func TestMedianRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []providerRate
+		want    string
+	}{
+		{name: "single", samples: newSamples("1.5"), want: "1.5"},
+		{name: "odd count", samples: newSamples("1", "2", "10"), want: "2"},
+		{name: "even count", samples: newSamples("1", "2", "3", "10"), want: "2.5"},
+		{name: "two", samples: newSamples("0.1", "0.2"), want: "0.15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := medianRate(tt.samples); !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("medianRate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimmedMeanRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		samples   []providerRate
+		trimRatio float64
+		want      string
+	}{
+		{
+			name:      "no trim",
+			samples:   newSamples("1", "2", "6"),
+			trimRatio: 0,
+			want:      "3",
+		},
+		{
+			name:      "trim one from each side",
+			samples:   newSamples("1", "2", "3", "4", "100"),
+			trimRatio: 0.2,
+			want:      "3",
+		},
+		{
+			name:      "ratio rounds down",
+			samples:   newSamples("1", "2", "3", "6"),
+			trimRatio: 0.2,
+			want:      "3",
+		},
+		{
+			name:      "half ratio keeps the middle of odd count",
+			samples:   newSamples("1", "2", "3", "4", "100"),
+			trimRatio: 0.5,
+			want:      "3",
+		},
+		{
+			name:      "half ratio keeps the middle of even count",
+			samples:   newSamples("1", "2", "4", "100"),
+			trimRatio: 0.5,
+			want:      "3",
+		},
+		{
+			name:      "ratio above half",
+			samples:   newSamples("1", "3"),
+			trimRatio: 0.9,
+			want:      "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimmedMeanRate(tt.samples, tt.trimRatio); !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("trimmedMeanRate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateRate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		aggregation   string
+		values        []string
+		wantOk        bool
+		wantValue     string
+		wantProviders []string
+		wantSpread    string
+	}{
+		{
+			name:          "median",
+			aggregation:   AggregationMedian,
+			values:        []string{"102", "100", "101"},
+			wantOk:        true,
+			wantValue:     "101",
+			wantProviders: []string{"b", "c", "a"},
+			wantSpread:    "0.0198019801980198",
+		},
+		{
+			name:          "outlier is dropped",
+			aggregation:   AggregationMedian,
+			values:        []string{"100", "101", "150"},
+			wantOk:        true,
+			wantValue:     "100.5",
+			wantProviders: []string{"a", "b"},
+			wantSpread:    "0.0099502487562189",
+		},
+		{
+			name:          "trimmed mean",
+			aggregation:   AggregationTrimmedMean,
+			values:        []string{"100", "101", "102", "103", "104"},
+			wantOk:        true,
+			wantValue:     "102",
+			wantProviders: []string{"a", "b", "c", "d", "e"},
+			wantSpread:    "0.0392156862745098",
+		},
+		{
+			name:          "trimmed mean without outlier",
+			aggregation:   AggregationTrimmedMean,
+			values:        []string{"100", "101", "102", "103", "200"},
+			wantOk:        true,
+			wantValue:     "101.5",
+			wantProviders: []string{"a", "b", "c", "d"},
+			wantSpread:    "0.0295566502463054",
+		},
+		{
+			name:          "single provider",
+			aggregation:   AggregationMedian,
+			values:        []string{"100"},
+			wantOk:        true,
+			wantValue:     "100",
+			wantProviders: []string{"a"},
+			wantSpread:    "0",
+		},
+		{
+			name:        "rates diverge",
+			aggregation: AggregationMedian,
+			values:      []string{"100", "200"},
+			wantOk:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RateCalculator{
+				Service: config.Service{
+					RateAggregation:  tt.aggregation,
+					RateMaxDeviation: 0.05,
+					RateTrimRatio:    0.2,
+				},
+			}
+
+			samples := newSamples(tt.values...)
+			for i := range samples {
+				samples[i].rate = Rate{
+					Base:      "ETH",
+					Quote:     USDT,
+					Value:     samples[i].value,
+					FetchedAt: now.Add(-time.Duration(i) * time.Second),
+				}
+			}
+
+			rate, ok := c.aggregateRate("ETH", samples)
+			if ok != tt.wantOk {
+				t.Fatalf("aggregateRate() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+
+			if !rate.Value.Equal(decimal.RequireFromString(tt.wantValue)) {
+				t.Errorf("value = %s, want %s", rate.Value, tt.wantValue)
+			}
+			if !slices.Equal(rate.Providers, tt.wantProviders) {
+				t.Errorf("providers = %v, want %v", rate.Providers, tt.wantProviders)
+			}
+			if !rate.Spread.Equal(decimal.RequireFromString(tt.wantSpread)) {
+				t.Errorf("spread = %s, want %s", rate.Spread, tt.wantSpread)
+			}
+			if rate.Base != "ETH" || rate.Quote != USDT {
+				t.Errorf("pair = %s, want ETH/USDT", rate.Pair())
+			}
+
+			// the oldest fetch time of the contributed rates
+			oldest := now
+			for _, sample := range samples {
+				if slices.Contains(rate.Providers, sample.provider) && sample.rate.FetchedAt.Before(oldest) {
+					oldest = sample.rate.FetchedAt
+				}
+			}
+			if !rate.FetchedAt.Equal(oldest) {
+				t.Errorf("fetched at = %s, want %s", rate.FetchedAt, oldest)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import "github.com/shopspring/decimal"
+
+// MaxAmountDigits bounds the integer and the fractional digits
+// of the amount, so the conversion arithmetic stays cheap
+const MaxAmountDigits = 30
+
+// ValidateAmount checks the amount to convert is positive and bounded,
+// the amount is not formatted since its exponent could be huge
+func ValidateAmount(amount decimal.Decimal) error {
+	exponent := int64(amount.Exponent())
+	intDigits := int64(amount.NumDigits()) + exponent
+
+	if !amount.IsPositive() || intDigits > MaxAmountDigits || -exponent > MaxAmountDigits {
+		return &ErrInvalidAmount{
+			MaxDigits: MaxAmountDigits,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  string
+		wantErr bool
+	}{
+		{name: "integer", amount: "100"},
+		{name: "fraction", amount: "0.000000000000000001"},
+		{name: "max integer digits", amount: strings.Repeat("9", MaxAmountDigits)},
+		{name: "max fractional digits", amount: "0." + strings.Repeat("1", MaxAmountDigits)},
+		{name: "zero", amount: "0", wantErr: true},
+		{name: "negative", amount: "-1", wantErr: true},
+		{name: "too many integer digits", amount: "1" + strings.Repeat("0", MaxAmountDigits), wantErr: true},
+		{name: "too many fractional digits", amount: "0." + strings.Repeat("0", MaxAmountDigits) + "1", wantErr: true},
+		{name: "huge exponent", amount: "1e1000000", wantErr: true},
+		{name: "tiny exponent", amount: "1e-1000000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAmount(decimal.RequireFromString(tt.amount))
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("ValidateAmount() = %v, want nil", err)
+				}
+				return
+			}
+
+			var invalidAmount *ErrInvalidAmount
+			if !errors.As(err, &invalidAmount) {
+				t.Errorf("ValidateAmount() = %v, want ErrInvalidAmount", err)
+			}
+		})
+	}
+}
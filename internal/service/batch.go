@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// BatchResult is the result of the batch item, either
+// the conversion or the error is set
+type BatchResult struct {
+	Conversion *Conversion
+	Err        error
+}
+
+// ConvertBatch converts all the items by the same rate graph, markups
+// and fees, so their updates could not affect the batch. Items with
+// invalid amounts fail on their own
+func (c *RateCalculator) ConvertBatch(
+	ctx context.Context,
+	reqs []ConvertRequest,
+) (res []BatchResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("panic while converting batch", "panic", r)
+			err = ErrServiceInternal
+		}
+	}()
+
+	if len(reqs) > c.ConvertBatchLimit {
+		return nil, &ErrBatchTooLarge{
+			Size: len(reqs),
+			Max:  c.ConvertBatchLimit,
+		}
+	}
+
+	prices := c.currentPricing()
+	now := time.Now()
+
+	res = make([]BatchResult, 0, len(reqs))
+	for _, req := range reqs {
+		if err := ValidateAmount(req.Amount); err != nil {
+			res = append(res, BatchResult{
+				Err: err,
+			})
+			continue
+		}
+
+		conversion, err := c.convert(prices, req, now)
+		res = append(res, BatchResult{
+			Conversion: conversion,
+			Err:        err,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestConvertBatchPartialFailure(t *testing.T) {
+	c := newQuoteCalculator()
+	c.ConvertBatchLimit = 10
+	c.pairTypesPolicy = PairTypesPolicy{{Base: Fiat, Quote: Crypto}: {}}
+	c.updateCurrencies([]Currency{{Code: "ETH", Type: Crypto, MinorUnits: 8, IsEnabled: true}})
+
+	tooManyDecimals := int64(defaultMaxDecimals) + 1
+
+	reqs := []ConvertRequest{
+		{Base: "USD", Quote: "EUR", Amount: decimal.NewFromInt(100), Rounding: RoundHalfUp},
+		{Base: "USD", Quote: "EUR", Amount: decimal.Zero, Rounding: RoundHalfUp},
+		{Base: "USD", Quote: "JPY", Amount: decimal.NewFromInt(100), Rounding: RoundHalfUp},
+		{Base: "USD", Quote: "ETH", Amount: decimal.NewFromInt(100), Rounding: RoundHalfUp},
+		{Base: "USD", Quote: "EUR", Amount: decimal.NewFromInt(100), Rounding: RoundHalfUp, Side: "bid"},
+		{Base: "USD", Quote: "EUR", Amount: decimal.NewFromInt(100), Rounding: RoundHalfUp, Decimals: &tooManyDecimals},
+		{Base: "EUR", Quote: "USD", Amount: decimal.NewFromInt(90), Rounding: RoundHalfUp, Side: SideBuy},
+	}
+
+	res, err := c.ConvertBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("ConvertBatch(): %v", err)
+	}
+	if len(res) != len(reqs) {
+		t.Fatalf("results count = %d, want %d", len(res), len(reqs))
+	}
+
+	var invalidAmount *ErrInvalidAmount
+	var notAvailable *ErrCurrencyNotAvailable
+	var invalidPair *ErrInvalidCurrencyPair
+	var unknownSide *ErrUnknownSide
+	var invalidDecimals *ErrInvalidDecimals
+
+	tests := []struct {
+		name string
+		// check is nil for the successful items
+		check      func(err error) bool
+		wantOutput string
+	}{
+		{name: "converted", wantOutput: "89.1"},
+		{name: "zero amount", check: func(err error) bool { return errors.As(err, &invalidAmount) }},
+		{name: "unknown currency", check: func(err error) bool { return errors.As(err, &notAvailable) }},
+		{name: "forbidden pair", check: func(err error) bool { return errors.As(err, &invalidPair) }},
+		{name: "unknown side", check: func(err error) bool { return errors.As(err, &unknownSide) }},
+		{name: "too many decimals", check: func(err error) bool { return errors.As(err, &invalidDecimals) }},
+		// 90 EUR by ask 1/0.9*1.01 without fee, the fee of the pair is directed
+		{name: "converted after failures", wantOutput: "101"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := res[i]
+			if tt.check != nil {
+				if result.Conversion != nil || !tt.check(result.Err) {
+					t.Errorf("item %d = %v/%v, want error", i, result.Conversion, result.Err)
+				}
+				return
+			}
+
+			if result.Err != nil {
+				t.Fatalf("item %d: %v", i, result.Err)
+			}
+			if !result.Conversion.Output.Equal(decimal.RequireFromString(tt.wantOutput)) {
+				t.Errorf("item %d output = %s, want %s", i, result.Conversion.Output, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestConvertBatchTooLarge(t *testing.T) {
+	c := newQuoteCalculator()
+	c.ConvertBatchLimit = 1
+
+	_, err := c.ConvertBatch(context.Background(), make([]ConvertRequest, 2))
+
+	var tooLarge *ErrBatchTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Errorf("ConvertBatch() = %v, want ErrBatchTooLarge", err)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+type CurrencyInfo struct {
+	Currency
+	// RateAvailable is set if the currency has the rate
+	// which is not older than its max age
+	RateAvailable bool
+}
+
+// ListCurrencies returns the enabled currencies sorted by code,
+// filtered by the type if it is not empty
+func (c *RateCalculator) ListCurrencies(currencyType string) []CurrencyInfo {
+	currencyType = strings.ToUpper(currencyType)
+	now := time.Now()
+
+	res := []CurrencyInfo{}
+	c.currencies.Range(func(code CurrencyCode, currency Currency) bool {
+		if currencyType != "" && string(currency.Type) != currencyType {
+			return true
+		}
+
+		rate, ok := c.ratesInUSD.Load(code)
+
+		res = append(res, CurrencyInfo{
+			Currency:      currency,
+			RateAvailable: ok && c.isRateFresh(&currency, rate, now),
+		})
+		return true
+	})
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Code < res[j].Code
+	})
+
+	return res
+}
+
+// isRateFresh checks the rate of the currency against its max age
+func (c *RateCalculator) isRateFresh(currency *Currency, rate *Rate, now time.Time) bool {
+	maxAge := c.maxRateAge(currency, rate.Stale)
+
+	return rate.FetchedAt.IsZero() || maxAge == 0 || now.Sub(rate.FetchedAt) <= maxAge
+}
@@ -4,11 +4,35 @@ import (
 	"blum-test/common/models"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 var ErrServiceStarted = errors.New("service is already started")
 var ErrServiceInternal = errors.New("service internal error")
 var ErrInvalidInternalRate = errors.New("invalid rate for pair, please try later")
+var ErrInvalidPollingInterval = errors.New("rate and currency polling intervals should be positive")
+
+type ErrInvalidRetryBackoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (e *ErrInvalidRetryBackoff) Error() string {
+	return fmt.Sprintf(
+		"invalid rate retry backoff [%s, %s], min should be positive and not above max",
+		e.Min,
+		e.Max,
+	)
+}
+
+type ErrUnknownAggregation struct {
+	Mode string
+}
+
+func (e *ErrUnknownAggregation) Error() string {
+	return fmt.Sprintf("unknown rate aggregation mode \"%s\"", e.Mode)
+}
 
 type ErrRateIsNotAvailable struct {
 	Code models.CurrencyCode
@@ -17,3 +41,129 @@ type ErrRateIsNotAvailable struct {
 func (e *ErrRateIsNotAvailable) Error() string {
 	return fmt.Sprintf("rate for \"%s\" is currently not available, please try later", e.Code)
 }
+
+type ErrRatesNotFetched struct {
+	Codes []models.CurrencyCode
+	// Err is the joined errors of the providers
+	Err error
+}
+
+func (e *ErrRatesNotFetched) Error() string {
+	codes := make([]string, 0, len(e.Codes))
+	for _, code := range e.Codes {
+		codes = append(codes, string(code))
+	}
+
+	return fmt.Sprintf("rates for \"%s\" could not be fetched from any provider", strings.Join(codes, ","))
+}
+
+func (e *ErrRatesNotFetched) Unwrap() error {
+	return e.Err
+}
+
+type ErrInvalidRate struct {
+	Code  models.CurrencyCode
+	Value string
+}
+
+func (e *ErrInvalidRate) Error() string {
+	return fmt.Sprintf("invalid rate of \"%s\" from response: %s", e.Code, e.Value)
+}
+
+type ErrNoConversionPath struct {
+	Base  models.CurrencyCode
+	Quote models.CurrencyCode
+}
+
+func (e *ErrNoConversionPath) Error() string {
+	return fmt.Sprintf("no rates to convert \"%s/%s\", please try later", e.Base, e.Quote)
+}
+
+type ErrUnknownSide struct {
+	Side string
+}
+
+func (e *ErrUnknownSide) Error() string {
+	return fmt.Sprintf("unknown side \"%s\", should be one of mid, sell, buy", e.Side)
+}
+
+type ErrUnknownRoundingMode struct {
+	Mode string
+}
+
+func (e *ErrUnknownRoundingMode) Error() string {
+	return fmt.Sprintf("unknown rounding mode \"%s\"", e.Mode)
+}
+
+type ErrInvalidDecimals struct {
+	Code     models.CurrencyCode
+	Decimals int64
+	Max      int32
+}
+
+func (e *ErrInvalidDecimals) Error() string {
+	return fmt.Sprintf(
+		"decimals %d is out of range for \"%s\", should be from 0 to %d",
+		e.Decimals, e.Code, e.Max,
+	)
+}
+
+type ErrInvalidAmount struct {
+	MaxDigits int
+}
+
+func (e *ErrInvalidAmount) Error() string {
+	return fmt.Sprintf(
+		"amount should be positive with at most %d integer and %d fractional digits",
+		e.MaxDigits, e.MaxDigits,
+	)
+}
+
+type ErrRateIsTooOld struct {
+	Pair      string
+	FetchedAt time.Time
+	MaxAge    time.Duration
+}
+
+func (e *ErrRateIsTooOld) Error() string {
+	return fmt.Sprintf(
+		"rate for \"%s\" fetched at %s is older than %s, please try later",
+		e.Pair, e.FetchedAt.UTC().Format(time.RFC3339), e.MaxAge,
+	)
+}
+
+type ErrNoRateSnapshot struct {
+	Code models.CurrencyCode
+	At   time.Time
+}
+
+func (e *ErrNoRateSnapshot) Error() string {
+	return fmt.Sprintf("no rate snapshot for \"%s\" at %s", e.Code, e.At.UTC().Format(time.RFC3339))
+}
+
+type ErrQuoteExpired struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+func (e *ErrQuoteExpired) Error() string {
+	return fmt.Sprintf("quote %s expired at %s", e.ID, e.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+type ErrBatchTooLarge struct {
+	Size int
+	Max  int
+}
+
+func (e *ErrBatchTooLarge) Error() string {
+	return fmt.Sprintf("batch of %d items exceeds the limit of %d items", e.Size, e.Max)
+}
+
+type ErrInvalidCurrency struct {
+	Field  string
+	Reason string
+}
+
+func (e *ErrInvalidCurrency) Error() string {
+	return fmt.Sprintf("invalid currency %s: %s", e.Field, e.Reason)
+}
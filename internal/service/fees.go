@@ -0,0 +1,195 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Charge is the fee breakdown of the conversion output,
+// all the amounts are in the quote currency
+type Charge struct {
+	Gross decimal.Decimal
+	Fee   decimal.Decimal
+	Net   decimal.Decimal
+}
+
+// feeTable is the fee schedules by their targets
+type feeTable struct {
+	pairs map[string]FeeSchedule
+	types map[CurrencyType]FeeSchedule
+}
+
+func newFeeTable(rules []FeeRule) *feeTable {
+	table := &feeTable{
+		pairs: map[string]FeeSchedule{},
+		types: map[CurrencyType]FeeSchedule{},
+	}
+
+	for _, rule := range rules {
+		target := strings.ToUpper(strings.TrimSpace(rule.Target))
+		switch rule.Scope {
+		case FeePair:
+			table.pairs[target] = append(table.pairs[target], rule)
+		case FeeType:
+			if hasAmounts(rule) {
+				log.Warn("fee rule of the currency type could have bps only, skipping",
+					slog.String("target", target))
+				continue
+			}
+			table.types[CurrencyType(target)] = append(table.types[CurrencyType(target)], rule)
+		default:
+			log.Warn("unknown fee rule scope", slog.String("scope", string(rule.Scope)))
+		}
+	}
+
+	for _, schedule := range table.pairs {
+		sortTiers(schedule)
+	}
+	for _, schedule := range table.types {
+		sortTiers(schedule)
+	}
+
+	return table
+}
+
+// hasAmounts reports whether the rule has the amounts in the quote
+// currency, the currencies of the type differ, so TYPE rules could
+// have the percentage only
+func hasAmounts(rule FeeRule) bool {
+	return !rule.MinAmount.IsZero() || !rule.Flat.IsZero() || !rule.MinFee.IsZero() || rule.MaxFee != nil
+}
+
+func sortTiers(schedule FeeSchedule) {
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].MinAmount.LessThan(schedule[j].MinAmount)
+	})
+}
+
+// schedules returns the fee schedules of the pair, schedule of the
+// pair takes precedence over the schedules of the currency types
+func (t *feeTable) schedules(pair *CurrencyPair) []FeeSchedule {
+	if t == nil {
+		return nil
+	}
+
+	if schedule, ok := t.pairs[string(pair.Base.Code)+"/"+string(pair.Quote.Code)]; ok {
+		return []FeeSchedule{schedule}
+	}
+
+	res := []FeeSchedule{}
+	if schedule, ok := t.types[pair.Base.Type]; ok {
+		res = append(res, schedule)
+	}
+	if schedule, ok := t.types[pair.Quote.Type]; ok && pair.Quote.Type != pair.Base.Type {
+		res = append(res, schedule)
+	}
+
+	return res
+}
+
+// scheduleFee returns the largest fee of the gross amount by the schedules
+func scheduleFee(schedules []FeeSchedule, gross decimal.Decimal) decimal.Decimal {
+	fee := decimal.Zero
+	for _, schedule := range schedules {
+		fee = decimal.Max(fee, tierFee(schedule, gross))
+	}
+
+	return fee
+}
+
+// tierFee applies the last tier which MinAmount is not above the gross,
+// the fee is capped by the gross amount
+func tierFee(schedule FeeSchedule, gross decimal.Decimal) decimal.Decimal {
+	var tier *FeeRule
+	for i := range schedule {
+		if schedule[i].MinAmount.GreaterThan(gross) {
+			break
+		}
+		tier = &schedule[i]
+	}
+
+	if tier == nil {
+		return decimal.Zero
+	}
+
+	fee := tier.Flat.Add(gross.Mul(decimal.NewFromInt32(tier.Bps)).Mul(basisPoint))
+	fee = decimal.Max(fee, tier.MinFee)
+	if tier.MaxFee != nil {
+		fee = decimal.Min(fee, *tier.MaxFee)
+	}
+
+	return decimal.Min(fee, gross)
+}
+
+// newCharge rounds the gross amount and the fee by the schedules,
+// net is the rest
+func newCharge(
+	schedules []FeeSchedule,
+	amount decimal.Decimal,
+	decimals int32,
+	rounding RoundingMode,
+) Charge {
+	gross := rounding.round(amount, decimals)
+	fee := rounding.round(scheduleFee(schedules, gross), decimals)
+
+	return Charge{
+		Gross: gross,
+		Fee:   fee,
+		Net:   gross.Sub(fee),
+	}
+}
+
+// loadFees replaces fee rules with the ones from the repository,
+// previous rules are kept on failure
+func (c *RateCalculator) loadFees(ctx context.Context) error {
+	rules, err := c.feeRepo.ListFeeRules(ctx)
+	if err != nil {
+		return fmt.Errorf("ListFeeRules(): %w", err)
+	}
+
+	c.fees.Store(newFeeTable(rules))
+	return nil
+}
+
+// listenFeeRuleUpdates reloads the fee rules on their events. While the
+// subscription is lost the fee rules are reloaded with the rates poll the
+// same way as markups
+func (c *RateCalculator) listenFeeRuleUpdates(ctx context.Context) error {
+	notificationChan, err := c.feeRepo.SubscribeToFeeRuleUpdates(ctx)
+	if err != nil {
+		log.Error("could not subscribe to fee rule updates, reloading fees with rates", slog.Any("error", err))
+		c.feesPolled.Store(true)
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("finishing polling fee rule updates")
+			return nil
+
+		case notification, ok := <-notificationChan:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				log.Error("fee rule updates subscription is lost, reloading fees with rates")
+				c.feesPolled.Store(true)
+				return nil
+			}
+
+			if err := c.loadFees(ctx); err != nil {
+				log.Error("could not reload fee rules", slog.Any("error", err))
+				continue
+			}
+
+			log.Info("fee rules reloaded", slog.String("operation", notification.Operation))
+		}
+	}
+}
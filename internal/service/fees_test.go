@@ -0,0 +1,273 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"blum-test/internal/repository"
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newFeeRule(scope FeeScope, target, minAmount, flat string, bps int32, minFee string, maxFee string) FeeRule {
+	rule := FeeRule{
+		Scope:     scope,
+		Target:    target,
+		MinAmount: decimal.RequireFromString(minAmount),
+		Flat:      decimal.RequireFromString(flat),
+		Bps:       bps,
+		MinFee:    decimal.RequireFromString(minFee),
+	}
+	if maxFee != "" {
+		value := decimal.RequireFromString(maxFee)
+		rule.MaxFee = &value
+	}
+
+	return rule
+}
+
+func TestTierFee(t *testing.T) {
+	schedule := FeeSchedule{
+		newFeeRule(FeePair, "USD/EUR", "10", "1", 100, "0", ""),
+		newFeeRule(FeePair, "USD/EUR", "1000", "0", 50, "8", "20"),
+		newFeeRule(FeePair, "USD/EUR", "100000", "0", 10, "0", ""),
+	}
+
+	tests := []struct {
+		name  string
+		gross string
+		want  string
+	}{
+		{name: "below the first tier", gross: "5", want: "0"},
+		{name: "first tier bound", gross: "10", want: "1.1"},
+		{name: "first tier", gross: "500", want: "6"},
+		{name: "min fee", gross: "1000", want: "8"},
+		{name: "bps of the second tier", gross: "3000", want: "15"},
+		{name: "max fee", gross: "50000", want: "20"},
+		{name: "last tier", gross: "200000", want: "200"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tierFee(schedule, decimal.RequireFromString(tt.gross))
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("tierFee(%s) = %s, want %s", tt.gross, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTierFeeIsCappedByGross(t *testing.T) {
+	schedule := FeeSchedule{
+		newFeeRule(FeePair, "USD/ETH", "0", "5", 0, "0", ""),
+	}
+
+	if got := tierFee(schedule, decimal.RequireFromString("3")); !got.Equal(decimal.RequireFromString("3")) {
+		t.Errorf("tierFee() = %s, want 3", got)
+	}
+	if got := tierFee(FeeSchedule{}, decimal.RequireFromString("3")); !got.IsZero() {
+		t.Errorf("tierFee() of empty schedule = %s, want 0", got)
+	}
+}
+
+func TestFeeTableSchedules(t *testing.T) {
+	table := newFeeTable([]FeeRule{
+		newFeeRule(FeePair, "usd/eur", "100", "2", 0, "0", ""),
+		newFeeRule(FeePair, "USD/EUR", "0", "1", 0, "0", ""),
+		newFeeRule(FeeType, "FIAT", "0", "0", 30, "0", ""),
+		newFeeRule(FeeType, "crypto", "0", "0", 40, "0", ""),
+		// amounts of the type rule are in the different currencies
+		newFeeRule(FeeType, "FIAT", "100", "0", 50, "0", ""),
+		newFeeRule(FeeType, "CRYPTO", "0", "1", 0, "0", ""),
+		newFeeRule("UNKNOWN", "USD/EUR", "0", "100", 0, "0", ""),
+	})
+
+	usd := Currency{Code: USD, Type: Fiat}
+	eur := Currency{Code: "EUR", Type: Fiat}
+	eth := Currency{Code: "ETH", Type: Crypto}
+
+	tests := []struct {
+		name string
+		pair *CurrencyPair
+		// wantFees are the fees of the first tiers of the schedules
+		// of 100 gross amount
+		wantFees []string
+	}{
+		{
+			name:     "pair schedule takes precedence",
+			pair:     &CurrencyPair{Base: usd, Quote: eur},
+			wantFees: []string{"2"},
+		},
+		{
+			name:     "pair is directed",
+			pair:     &CurrencyPair{Base: eur, Quote: usd},
+			wantFees: []string{"0.3"},
+		},
+		{
+			name:     "schedules of both types",
+			pair:     &CurrencyPair{Base: usd, Quote: eth},
+			wantFees: []string{"0.3", "0.4"},
+		},
+		{
+			name:     "same types schedule once",
+			pair:     &CurrencyPair{Base: eth, Quote: eth},
+			wantFees: []string{"0.4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules := table.schedules(tt.pair)
+			if len(schedules) != len(tt.wantFees) {
+				t.Fatalf("schedules count = %d, want %d: %v", len(schedules), len(tt.wantFees), schedules)
+			}
+			for i, schedule := range schedules {
+				fee := tierFee(schedule, decimal.NewFromInt(100))
+				if !fee.Equal(decimal.RequireFromString(tt.wantFees[i])) {
+					t.Errorf("schedule %d fee = %s, want %s", i, fee, tt.wantFees[i])
+				}
+			}
+		})
+	}
+
+	// tiers are sorted by MinAmount
+	schedule := table.pairs["USD/EUR"]
+	if len(schedule) != 2 || !schedule[1].MinAmount.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("pair schedule = %v, want 2 tiers sorted by min amount", schedule)
+	}
+
+	for currencyType, schedule := range table.types {
+		if len(schedule) != 1 {
+			t.Errorf("%s schedule = %v, want the bps rule only", currencyType, schedule)
+		}
+	}
+
+	var nilTable *feeTable
+	if schedules := nilTable.schedules(&CurrencyPair{Base: usd, Quote: eur}); len(schedules) != 0 {
+		t.Errorf("schedules of nil table = %v, want none", schedules)
+	}
+}
+
+func TestNewCharge(t *testing.T) {
+	schedules := []FeeSchedule{
+		{newFeeRule(FeeType, "FIAT", "0", "0", 100, "0", "")},
+		{newFeeRule(FeePair, "USD/ETH", "0", "0.5", 0, "0", "")},
+	}
+
+	tests := []struct {
+		name      string
+		schedules []FeeSchedule
+		amount    string
+		decimals  int32
+		rounding  RoundingMode
+		want      Charge
+	}{
+		{
+			name:      "largest fee of the schedules",
+			schedules: schedules,
+			amount:    "100.004",
+			decimals:  2,
+			rounding:  RoundHalfUp,
+			want:      newTestCharge("100", "1", "99"),
+		},
+		{
+			name:      "flat fee is larger",
+			schedules: schedules,
+			amount:    "20",
+			decimals:  2,
+			rounding:  RoundHalfUp,
+			want:      newTestCharge("20", "0.5", "19.5"),
+		},
+		{
+			name:      "fee is rounded",
+			schedules: schedules,
+			amount:    "123.456",
+			decimals:  2,
+			rounding:  RoundFloor,
+			want:      newTestCharge("123.45", "1.23", "122.22"),
+		},
+		{
+			name:     "no schedules",
+			amount:   "1.005",
+			decimals: 2,
+			rounding: RoundHalfEven,
+			want:     newTestCharge("1", "0", "1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newCharge(tt.schedules, decimal.RequireFromString(tt.amount), tt.decimals, tt.rounding)
+			if !got.Gross.Equal(tt.want.Gross) || !got.Fee.Equal(tt.want.Fee) || !got.Net.Equal(tt.want.Net) {
+				t.Errorf("newCharge() = %v/%v/%v, want %v/%v/%v",
+					got.Gross, got.Fee, got.Net, tt.want.Gross, tt.want.Fee, tt.want.Net)
+			}
+		})
+	}
+}
+
+func newTestCharge(gross, fee, net string) Charge {
+	return Charge{
+		Gross: decimal.RequireFromString(gross),
+		Fee:   decimal.RequireFromString(fee),
+		Net:   decimal.RequireFromString(net),
+	}
+}
+
+type fakeFeeRepo struct {
+	rules        []FeeRule
+	subscription chan repository.FeeRuleNotification
+}
+
+func (r *fakeFeeRepo) ListFeeRules(ctx context.Context) ([]FeeRule, error) {
+	return r.rules, nil
+}
+
+func (r *fakeFeeRepo) SubscribeToFeeRuleUpdates(ctx context.Context) (<-chan repository.FeeRuleNotification, error) {
+	if r.subscription == nil {
+		return nil, errDatabaseDown
+	}
+
+	return r.subscription, nil
+}
+
+func TestListenFeeRuleUpdates(t *testing.T) {
+	repo := &fakeFeeRepo{
+		rules:        []FeeRule{newFeeRule(FeeType, "FIAT", "0", "0", 10, "0", "")},
+		subscription: make(chan repository.FeeRuleNotification),
+	}
+	c := &RateCalculator{
+		feeRepo: repo,
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- c.listenFeeRuleUpdates(context.Background())
+	}()
+
+	repo.subscription <- repository.FeeRuleNotification{Operation: "INSERT"}
+	waitFor(t, func() bool {
+		return c.fees.Load() != nil
+	})
+
+	close(repo.subscription)
+	if err := <-done; err != nil {
+		t.Errorf("listenFeeRuleUpdates() = %v, want nil on lost subscription", err)
+	}
+	if !c.feesPolled.Load() {
+		t.Error("fees are not reloaded with rates after the subscription is lost")
+	}
+}
+
+func TestListenFeeRuleUpdatesWithoutSubscription(t *testing.T) {
+	c := &RateCalculator{
+		feeRepo: &fakeFeeRepo{},
+	}
+
+	if err := c.listenFeeRuleUpdates(context.Background()); err != nil {
+		t.Errorf("listenFeeRuleUpdates() = %v, want nil", err)
+	}
+	if !c.feesPolled.Load() {
+		t.Error("fees are not reloaded with rates without the subscription")
+	}
+}
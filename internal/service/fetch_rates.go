@@ -0,0 +1,291 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"blum-test/internal/clients"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// fetchRates updates quotes of the currencies according to the
+// configured aggregation mode and recalculates rates in USD, only
+// rates of the currencies which whole quote path is refreshed by
+// the cycle are persisted. The count of the updated quotes is
+// returned along with the error of the partial update
+func (c *RateCalculator) fetchRates(
+	ctx context.Context,
+	currencies map[CurrencyCode]Currency,
+) (int, error) {
+	currencies = c.withAnchors(currencies)
+
+	// quotes fetched by the cycle are not older than its start
+	cycleStart := time.Now()
+
+	var rates map[CurrencyCode]Rate
+	var err error
+
+	switch c.RateAggregation {
+	case AggregationMedian, AggregationTrimmedMean:
+		rates, err = c.fetchAggregatedRates(ctx, currencies)
+	default:
+		rates, err = c.fetchFailoverRates(ctx, currencies)
+	}
+
+	for code, rate := range rates {
+		c.quotes.Store(code, &rate)
+		log.Debug(
+			"rate updated",
+			slog.String("pair", rate.Pair()),
+			slog.String("rate", rate.Value.String()),
+			slog.Any("providers", rate.Providers),
+			slog.String("spread", rate.Spread.String()),
+		)
+	}
+
+	ratesInUSD := []Rate{}
+	for _, rate := range c.updateRatesInUSD() {
+		if _, ok := currencies[rate.Quote]; ok && !rate.FetchedAt.Before(cycleStart) {
+			ratesInUSD = append(ratesInUSD, rate)
+		}
+	}
+
+	if len(rates) > 0 {
+		c.saveRateSnapshots(ctx, ratesInUSD)
+	}
+
+	return len(rates), err
+}
+
+// saveRateSnapshots persists fetched rates in USD of the fetch cycle,
+// failure to persist does not affect the rates update. USD itself and
+// rates without fetch time are not fetched, so they are skipped
+func (c *RateCalculator) saveRateSnapshots(ctx context.Context, rates []Rate) {
+	snapshots := make([]RateSnapshot, 0, len(rates))
+	for _, rate := range rates {
+		if rate.Stale || rate.Quote == USD || rate.FetchedAt.IsZero() {
+			continue
+		}
+		snapshots = append(snapshots, RateSnapshot{
+			Code:      rate.Quote,
+			RateInUSD: rate.Value,
+			Provider:  strings.Join(rate.Providers, ","),
+			FetchedAt: rate.FetchedAt,
+		})
+	}
+
+	if err := c.rateRepo.SaveRateSnapshots(ctx, snapshots); err != nil {
+		log.Error("could not save rate snapshots", slog.Any("error", err))
+	}
+}
+
+// fetchFailoverRates requests quotes of the currencies from the providers
+// in the priority order. Currencies which quotes could not be fetched from
+// the provider (provider failure, rate limit, timeout or missing rate)
+// are requested from the next one.
+func (c *RateCalculator) fetchFailoverRates(
+	ctx context.Context,
+	currencies map[CurrencyCode]Currency,
+) (map[CurrencyCode]Rate, error) {
+	pending := make(map[CurrencyCode]Currency, len(currencies))
+	for code, currency := range currencies {
+		if c.anchorOf(currency) != code {
+			pending[code] = currency
+		}
+	}
+
+	rates := make(map[CurrencyCode]Rate, len(currencies))
+	errs := []error{}
+	for _, provider := range c.providers {
+		if len(pending) == 0 {
+			break
+		}
+
+		providerRates, err := c.fetchProviderRates(ctx, provider, pending)
+		if err != nil {
+			log.Warn(
+				"could not fetch rates from provider, falling through to the next one",
+				slog.String("provider", provider.Name()),
+				slog.Any("error", err),
+			)
+			errs = append(errs, err)
+		}
+
+		for code, rate := range providerRates {
+			if _, ok := pending[code]; !ok {
+				continue
+			}
+			rates[code] = rate
+			delete(pending, code)
+		}
+	}
+
+	if len(pending) > 0 {
+		codes := make([]CurrencyCode, 0, len(pending))
+		for code := range pending {
+			codes = append(codes, code)
+		}
+
+		return rates, &ErrRatesNotFetched{
+			Codes: codes,
+			Err:   errors.Join(errs...),
+		}
+	}
+
+	return rates, nil
+}
+
+type quoteGroup struct {
+	currencyType CurrencyType
+	anchor       CurrencyCode
+}
+
+// fetchProviderRates returns quotes of the currencies against their
+// anchors fetched from the provider, the quotes fetched before
+// the failure are returned along with the error
+func (c *RateCalculator) fetchProviderRates(
+	ctx context.Context,
+	provider clients.IRateProvider,
+	currencies map[CurrencyCode]Currency,
+) (map[CurrencyCode]Rate, error) {
+	groups := map[quoteGroup][]CurrencyCode{}
+	for code, currency := range currencies {
+		anchor := c.anchorOf(currency)
+		if anchor == code {
+			continue
+		}
+
+		group := quoteGroup{
+			currencyType: currency.Type,
+			anchor:       anchor,
+		}
+		groups[group] = append(groups[group], code)
+	}
+
+	res := map[CurrencyCode]Rate{}
+	errs := []error{}
+
+	for group, codes := range groups {
+		var err error
+		switch group.currencyType {
+		case Fiat:
+			err = fetchFiatRates(ctx, provider, group.anchor, codes, res)
+		case Crypto:
+			err = fetchCryptoRates(ctx, provider, codes, group.anchor, res)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// fetchFiatRates requests quotes "base/code" of the fiat currencies
+func fetchFiatRates(
+	ctx context.Context,
+	provider clients.IRateProvider,
+	base CurrencyCode,
+	codes []CurrencyCode,
+	res map[CurrencyCode]Rate,
+) error {
+	fiatRates, err := provider.GetFiatRates(ctx, base, codes)
+	if err != nil {
+		return fmt.Errorf("GetFiatRates(): %w", err)
+	}
+
+	fetchedAt := time.Now()
+
+	parsed, err := parseRates(fiatRates.Rates, codes)
+
+	for code, value := range parsed {
+		res[code] = Rate{
+			Base:      base,
+			Quote:     code,
+			Value:     value,
+			Providers: []string{provider.Name()},
+			Spread:    decimal.Zero,
+			FetchedAt: fetchedAt,
+		}
+	}
+
+	return err
+}
+
+// fetchCryptoRates requests quotes "code/quote" of the crypto currencies,
+// quotes of the failed batches are skipped
+func fetchCryptoRates(
+	ctx context.Context,
+	provider clients.IRateProvider,
+	codes []CurrencyCode,
+	quote CurrencyCode,
+	res map[CurrencyCode]Rate,
+) error {
+	cryptoRates, fetchErr := provider.GetCryptoRates(ctx, codes, quote)
+	if fetchErr != nil {
+		fetchErr = fmt.Errorf("GetCryptoRates(): %w", fetchErr)
+		if cryptoRates == nil {
+			return fetchErr
+		}
+	}
+
+	fetchedAt := time.Now()
+
+	parsed, parseErr := parseRates(cryptoRates.Rates, codes)
+
+	for code, value := range parsed {
+		res[code] = Rate{
+			Base:      code,
+			Quote:     quote,
+			Value:     value,
+			Providers: []string{provider.Name()},
+			Spread:    decimal.Zero,
+			FetchedAt: fetchedAt,
+		}
+	}
+
+	return errors.Join(fetchErr, parseErr)
+}
+
+// parseRates parses rates of the requested codes without intermediate
+// float conversion, invalid rates are skipped and reported along with
+// the valid ones
+func parseRates(
+	rates map[CurrencyCode]json.Number,
+	codes []CurrencyCode,
+) (map[CurrencyCode]decimal.Decimal, error) {
+	res := make(map[CurrencyCode]decimal.Decimal, len(codes))
+	errs := []error{}
+	for _, code := range codes {
+		number, ok := rates[code]
+		if !ok {
+			continue
+		}
+
+		value, err := decimal.NewFromString(number.String())
+		if err != nil || !value.IsPositive() {
+			log.Error(
+				"invalid rate",
+				slog.String("currency_code", string(code)),
+				slog.String("actual_value", number.String()),
+				slog.Any("error", err),
+			)
+			errs = append(errs, &ErrInvalidRate{
+				Code:  code,
+				Value: number.String(),
+			})
+			continue
+		}
+
+		res[code] = value
+	}
+
+	return res, errors.Join(errs...)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"blum-test/internal/clients"
+	"blum-test/internal/clients/static"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+var errProviderDown = errors.New("provider is down")
+
+func testCurrencies() map[CurrencyCode]Currency {
+	return map[CurrencyCode]Currency{
+		"EUR": {Code: "EUR", Type: Fiat},
+		"ETH": {Code: "ETH", Type: Crypto},
+		USDT:  {Code: USDT, Type: Crypto},
+	}
+}
+
+func newFailoverProviders() (*static.Provider, *static.Provider) {
+	primary := static.NewProvider("primary", map[CurrencyCode]decimal.Decimal{
+		"EUR": decimal.RequireFromString("0.9"),
+		"ETH": decimal.RequireFromString("0.0004"),
+		USDT:  decimal.RequireFromString("1"),
+	})
+	secondary := static.NewProvider("secondary", map[CurrencyCode]decimal.Decimal{
+		"EUR": decimal.RequireFromString("0.8"),
+		"ETH": decimal.RequireFromString("0.0005"),
+		USDT:  decimal.RequireFromString("1"),
+	})
+
+	return primary, secondary
+}
+
+func TestFetchFailoverRates(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup breaks the providers
+		setup         func(primary, secondary *static.Provider)
+		wantProviders map[CurrencyCode]string
+		wantNotFound  []CurrencyCode
+	}{
+		{
+			name:  "primary is healthy",
+			setup: func(primary, secondary *static.Provider) {},
+			wantProviders: map[CurrencyCode]string{
+				"EUR": "primary",
+				"ETH": "primary",
+				USDT:  "primary",
+			},
+		},
+		{
+			name: "primary is down",
+			setup: func(primary, secondary *static.Provider) {
+				primary.SetError(errProviderDown)
+			},
+			wantProviders: map[CurrencyCode]string{
+				"EUR": "secondary",
+				"ETH": "secondary",
+				USDT:  "secondary",
+			},
+		},
+		{
+			name: "primary is rate limited",
+			setup: func(primary, secondary *static.Provider) {
+				primary.SetError(&clients.ErrRateLimited{
+					Provider: primary.Name(),
+					Err:      errProviderDown,
+				})
+			},
+			wantProviders: map[CurrencyCode]string{
+				"EUR": "secondary",
+				"ETH": "secondary",
+				USDT:  "secondary",
+			},
+		},
+		{
+			name: "primary misses the rate",
+			setup: func(primary, secondary *static.Provider) {
+				primary.SetRates(map[CurrencyCode]decimal.Decimal{
+					"ETH": decimal.Zero,
+				})
+			},
+			wantProviders: map[CurrencyCode]string{
+				"EUR": "primary",
+				"ETH": "secondary",
+				USDT:  "primary",
+			},
+		},
+		{
+			name: "all providers are down",
+			setup: func(primary, secondary *static.Provider) {
+				primary.SetError(errProviderDown)
+				secondary.SetError(errProviderDown)
+			},
+			wantProviders: map[CurrencyCode]string{},
+			wantNotFound:  []CurrencyCode{"ETH", "EUR", USDT},
+		},
+		{
+			name: "rate is missed by all providers",
+			setup: func(primary, secondary *static.Provider) {
+				primary.SetError(errProviderDown)
+				secondary.SetRates(map[CurrencyCode]decimal.Decimal{
+					"EUR": decimal.Zero,
+				})
+			},
+			wantProviders: map[CurrencyCode]string{
+				"ETH": "secondary",
+				USDT:  "secondary",
+			},
+			wantNotFound: []CurrencyCode{"EUR"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, secondary := newFailoverProviders()
+			tt.setup(primary, secondary)
+
+			c := &RateCalculator{
+				providers: []clients.IRateProvider{primary, secondary},
+			}
+
+			rates, err := c.fetchFailoverRates(context.Background(), testCurrencies())
+
+			if len(tt.wantNotFound) == 0 && err != nil {
+				t.Fatalf("fetchFailoverRates(): %v", err)
+			}
+			if len(tt.wantNotFound) > 0 {
+				var notFetched *ErrRatesNotFetched
+				if !errors.As(err, &notFetched) {
+					t.Fatalf("error = %v, want ErrRatesNotFetched", err)
+				}
+				slices.Sort(notFetched.Codes)
+				if !slices.Equal(notFetched.Codes, tt.wantNotFound) {
+					t.Errorf("not fetched codes = %v, want %v", notFetched.Codes, tt.wantNotFound)
+				}
+			}
+
+			if len(rates) != len(tt.wantProviders) {
+				t.Errorf("rates count = %d, want %d: %v", len(rates), len(tt.wantProviders), rates)
+			}
+			for code, provider := range tt.wantProviders {
+				rate, ok := rates[code]
+				if !ok {
+					t.Errorf("rate of %s is not fetched", code)
+					continue
+				}
+				if !slices.Equal(rate.Providers, []string{provider}) {
+					t.Errorf("providers of %s = %v, want [%s]", code, rate.Providers, provider)
+				}
+				if rate.FetchedAt.IsZero() {
+					t.Errorf("fetch time of %s is not set", code)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchFailoverRatesValues(t *testing.T) {
+	primary, secondary := newFailoverProviders()
+	primary.SetRates(map[CurrencyCode]decimal.Decimal{
+		"ETH": decimal.Zero,
+	})
+
+	c := &RateCalculator{
+		providers: []clients.IRateProvider{primary, secondary},
+	}
+
+	rates, err := c.fetchFailoverRates(context.Background(), testCurrencies())
+	if err != nil {
+		t.Fatalf("fetchFailoverRates(): %v", err)
+	}
+
+	// fiat is quoted as USD/EUR, crypto as ETH/USDT
+	tests := []struct {
+		code        CurrencyCode
+		base, quote CurrencyCode
+		value       string
+	}{
+		{code: "EUR", base: USD, quote: "EUR", value: "0.9"},
+		{code: "ETH", base: "ETH", quote: USDT, value: "2000"},
+	}
+
+	for _, tt := range tests {
+		rate := rates[tt.code]
+		if rate.Base != tt.base || rate.Quote != tt.quote {
+			t.Errorf("pair of %s = %s, want %s/%s", tt.code, rate.Pair(), tt.base, tt.quote)
+		}
+		if !rate.Value.Equal(decimal.RequireFromString(tt.value)) {
+			t.Errorf("rate of %s = %s, want %s", tt.code, rate.Value, tt.value)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	. "blum-test/common/models"
+)
+
+// rateGraph is the graph of currencies where every provider quote
+// is the edge, each quote is stored in both directions
+type rateGraph map[CurrencyCode]map[CurrencyCode]Rate
+
+func newRateGraph(quotes []Rate) rateGraph {
+	g := make(rateGraph)
+	add := func(rate Rate) {
+		if _, ok := g[rate.Base]; !ok {
+			g[rate.Base] = make(map[CurrencyCode]Rate)
+		}
+		g[rate.Base][rate.Quote] = rate
+	}
+
+	for _, quote := range quotes {
+		if quote.Value.IsZero() {
+			continue
+		}
+		add(quote)
+		add(quote.invert())
+	}
+
+	return g
+}
+
+// findPath returns the shortest path of the rates from one currency
+// to another, only pivots could be intermediate currencies. Paths of
+// the same length are chosen by the pivots priority order.
+func (g rateGraph) findPath(from, to CurrencyCode, pivots []CurrencyCode) ([]Rate, bool) {
+	if from == to {
+		return []Rate{}, true
+	}
+
+	prev := map[CurrencyCode]Rate{}
+	visited := map[CurrencyCode]bool{from: true}
+	queue := []CurrencyCode{from}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		if rate, ok := g[node][to]; ok {
+			prev[to] = rate
+			return restorePath(prev, from, to), true
+		}
+
+		for _, pivot := range pivots {
+			rate, ok := g[node][pivot]
+			if !ok || visited[pivot] {
+				continue
+			}
+
+			visited[pivot] = true
+			prev[pivot] = rate
+			queue = append(queue, pivot)
+		}
+	}
+
+	return nil, false
+}
+
+func restorePath(prev map[CurrencyCode]Rate, from, to CurrencyCode) []Rate {
+	path := []Rate{}
+	for node := to; node != from; node = prev[node].Base {
+		path = append(path, prev[node])
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"slices"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newQuote(base, quote CurrencyCode, value string) Rate {
+	return Rate{
+		Base:  base,
+		Quote: quote,
+		Value: decimal.RequireFromString(value),
+	}
+}
+
+func pathPairs(path []Rate) []string {
+	pairs := make([]string, 0, len(path))
+	for _, rate := range path {
+		pairs = append(pairs, rate.Pair())
+	}
+
+	return pairs
+}
+
+func TestFindPath(t *testing.T) {
+	graph := newRateGraph([]Rate{
+		newQuote(USD, "EUR", "0.9"),
+		newQuote(USD, USDT, "1"),
+		newQuote("ETH", USDT, "2000"),
+		newQuote("BTC", USDT, "60000"),
+		newQuote("XMR", "BTC", "0.003"),
+		newQuote(USD, "CNY", "7"),
+		newQuote(USDT, "CNY", "7.1"),
+		newQuote(USDT, "EUR", "0.91"),
+		// zero quotes are not the edges
+		newQuote(USD, "RUB", "0"),
+	})
+
+	tests := []struct {
+		name     string
+		from, to CurrencyCode
+		pivots   []CurrencyCode
+		want     []string
+		wantOk   bool
+	}{
+		{
+			name:   "same currency",
+			from:   "EUR",
+			to:     "EUR",
+			want:   []string{},
+			wantOk: true,
+		},
+		{
+			name:   "direct quote",
+			from:   USD,
+			to:     "EUR",
+			want:   []string{"USD/EUR"},
+			wantOk: true,
+		},
+		{
+			name:   "inverted quote",
+			from:   USDT,
+			to:     "ETH",
+			want:   []string{"USDT/ETH"},
+			wantOk: true,
+		},
+		{
+			name:   "through pivot",
+			from:   "ETH",
+			to:     USD,
+			pivots: []CurrencyCode{USD, USDT},
+			want:   []string{"ETH/USDT", "USDT/USD"},
+			wantOk: true,
+		},
+		{
+			name:   "through several pivots",
+			from:   "XMR",
+			to:     "CNY",
+			pivots: []CurrencyCode{USD, USDT, "BTC"},
+			want:   []string{"XMR/BTC", "BTC/USDT", "USDT/CNY"},
+			wantOk: true,
+		},
+		{
+			name:   "shortest path is preferred",
+			from:   "ETH",
+			to:     "CNY",
+			pivots: []CurrencyCode{USD, USDT},
+			want:   []string{"ETH/USDT", "USDT/CNY"},
+			wantOk: true,
+		},
+		{
+			name:   "pivots priority",
+			from:   "EUR",
+			to:     "CNY",
+			pivots: []CurrencyCode{USD, USDT},
+			want:   []string{"EUR/USD", "USD/CNY"},
+			wantOk: true,
+		},
+		{
+			name:   "reversed pivots priority",
+			from:   "EUR",
+			to:     "CNY",
+			pivots: []CurrencyCode{USDT, USD},
+			want:   []string{"EUR/USDT", "USDT/CNY"},
+			wantOk: true,
+		},
+		{
+			name:   "intermediate currency is not pivot",
+			from:   "XMR",
+			to:     "ETH",
+			pivots: []CurrencyCode{USD, USDT},
+			wantOk: false,
+		},
+		{
+			name:   "no pivots",
+			from:   "ETH",
+			to:     USD,
+			wantOk: false,
+		},
+		{
+			name:   "zero quote",
+			from:   USD,
+			to:     "RUB",
+			pivots: []CurrencyCode{USD, USDT},
+			wantOk: false,
+		},
+		{
+			name:   "unknown currency",
+			from:   "GBP",
+			to:     USD,
+			pivots: []CurrencyCode{USD, USDT},
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := graph.findPath(tt.from, tt.to, tt.pivots)
+			if ok != tt.wantOk {
+				t.Fatalf("findPath() ok = %v, want %v, path %v", ok, tt.wantOk, pathPairs(path))
+			}
+			if !ok {
+				return
+			}
+
+			if got := pathPairs(path); !slices.Equal(got, tt.want) {
+				t.Errorf("findPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRateGraphInvertsQuotes(t *testing.T) {
+	graph := newRateGraph([]Rate{newQuote(USD, "EUR", "0.8")})
+
+	if got := graph[USD]["EUR"].Value; !got.Equal(decimal.RequireFromString("0.8")) {
+		t.Errorf("USD/EUR = %s, want 0.8", got)
+	}
+	if got := graph["EUR"][USD].Value; !got.Equal(decimal.RequireFromString("1.25")) {
+		t.Errorf("EUR/USD = %s, want 1.25", got)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HistoricalConversion is the result of the conversion by the
+// rate snapshots stored not after the requested moment
+type HistoricalConversion struct {
+	Output   decimal.Decimal
+	Decimals int32
+	Rate     Rate
+	// SnapshotTime is the fetch time of the oldest snapshot used
+	SnapshotTime time.Time
+}
+
+// ConvertHistorical converts the amount by the latest rate snapshots
+// of the currencies fetched not after the moment, unlike Convert the
+// amount is validated since there are no legacy clients of it
+func (c *RateCalculator) ConvertHistorical(
+	ctx context.Context,
+	req ConvertRequest,
+	at time.Time,
+) (*HistoricalConversion, error) {
+	if err := ValidateAmount(req.Amount); err != nil {
+		return nil, err
+	}
+
+	pair, decimals, err := c.validateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := []CurrencyCode{}
+	for _, code := range []CurrencyCode{pair.Base.Code, pair.Quote.Code} {
+		if code != USD {
+			codes = append(codes, code)
+		}
+	}
+
+	snapshots, err := c.rateRepo.GetRateSnapshotsAt(ctx, codes, at)
+	if err != nil {
+		log.Error("could not get rate snapshots", slog.Any("error", err))
+		return nil, ErrServiceInternal
+	}
+
+	snapshotTime := at
+	rateInUSD := func(code CurrencyCode) (decimal.Decimal, error) {
+		if code == USD {
+			return decimal.NewFromInt(1), nil
+		}
+
+		snapshot, ok := snapshots[code]
+		if !ok || !snapshot.RateInUSD.IsPositive() {
+			return decimal.Zero, &ErrNoRateSnapshot{
+				Code: code,
+				At:   at,
+			}
+		}
+
+		if snapshot.FetchedAt.Before(snapshotTime) {
+			snapshotTime = snapshot.FetchedAt
+		}
+
+		return snapshot.RateInUSD, nil
+	}
+
+	baseRate, err := rateInUSD(pair.Base.Code)
+	if err != nil {
+		return nil, err
+	}
+	quoteRate, err := rateInUSD(pair.Quote.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := Rate{
+		Base:      pair.Base.Code,
+		Quote:     pair.Quote.Code,
+		Providers: []string{},
+		Spread:    decimal.Zero,
+		FetchedAt: snapshotTime,
+	}.withFraction(quoteRate, baseRate)
+
+	return &HistoricalConversion{
+		Output:       req.Rounding.round(rate.convert(req.Amount, decimals), decimals),
+		Decimals:     decimals,
+		Rate:         rate,
+		SnapshotTime: snapshotTime,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type Side string
+
+const (
+	// SideMid converts by the mid rate without markup
+	SideMid Side = "mid"
+	// SideSell converts the amount the client sells by the bid rate
+	SideSell Side = "sell"
+	// SideBuy converts the amount the client buys by the ask rate
+	SideBuy Side = "buy"
+)
+
+func ParseSide(side string) (Side, error) {
+	if side == "" {
+		return SideMid, nil
+	}
+
+	switch res := Side(strings.ToLower(side)); res {
+	case SideMid, SideSell, SideBuy:
+		return res, nil
+	}
+
+	return "", &ErrUnknownSide{
+		Side: side,
+	}
+}
+
+var basisPoint = decimal.New(1, -4)
+
+// markupTable is the markups in basis points by their targets
+type markupTable struct {
+	pairs      map[string]int32
+	currencies map[CurrencyCode]int32
+	types      map[CurrencyType]int32
+}
+
+func newMarkupTable(markups []Markup) *markupTable {
+	table := &markupTable{
+		pairs:      map[string]int32{},
+		currencies: map[CurrencyCode]int32{},
+		types:      map[CurrencyType]int32{},
+	}
+
+	for _, markup := range markups {
+		target := strings.ToUpper(strings.TrimSpace(markup.Target))
+		switch markup.Scope {
+		case MarkupPair:
+			table.pairs[target] = markup.Bps
+		case MarkupCurrency:
+			table.currencies[CurrencyCode(target)] = markup.Bps
+		case MarkupType:
+			table.types[CurrencyType(target)] = markup.Bps
+		default:
+			log.Warn("unknown markup scope", slog.String("scope", string(markup.Scope)))
+		}
+	}
+
+	return table
+}
+
+// bps returns markup of the pair, pair markup takes precedence,
+// otherwise the larger one of the currencies markups is used,
+// currency markup takes precedence over its type markup
+func (t *markupTable) bps(pair *CurrencyPair) int32 {
+	if bps, ok := t.pairs[string(pair.Base.Code)+"/"+string(pair.Quote.Code)]; ok {
+		return bps
+	}
+	if bps, ok := t.pairs[string(pair.Quote.Code)+"/"+string(pair.Base.Code)]; ok {
+		return bps
+	}
+
+	return max(t.currencyBps(&pair.Base), t.currencyBps(&pair.Quote))
+}
+
+func (t *markupTable) currencyBps(currency *Currency) int32 {
+	if bps, ok := t.currencies[currency.Code]; ok {
+		return bps
+	}
+
+	return t.types[currency.Type]
+}
+
+// loadMarkups replaces markups with the ones from the repository,
+// previous markups are kept on failure
+func (c *RateCalculator) loadMarkups(ctx context.Context) error {
+	markups, err := c.markupRepo.ListMarkups(ctx)
+	if err != nil {
+		return fmt.Errorf("ListMarkups(): %w", err)
+	}
+
+	c.markups.Store(newMarkupTable(markups))
+	return nil
+}
+
+// bidAsk returns bid and ask rates of the pair around the mid rate
+func (t *markupTable) bidAsk(pair *CurrencyPair, mid decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	bid, ask := t.factors(pair)
+
+	return mid.Mul(bid), mid.Mul(ask)
+}
+
+// factors returns multipliers of the mid rate to get bid and ask rates
+func (t *markupTable) factors(pair *CurrencyPair) (decimal.Decimal, decimal.Decimal) {
+	one := decimal.NewFromInt(1)
+	if t == nil {
+		return one, one
+	}
+
+	markup := decimal.NewFromInt32(t.bps(pair)).Mul(basisPoint)
+
+	return one.Sub(markup), one.Add(markup)
+}
+
+// sideFactor returns multiplier of the mid rate for the side
+func (t *markupTable) sideFactor(pair *CurrencyPair, side Side) decimal.Decimal {
+	bid, ask := t.factors(pair)
+	switch side {
+	case SideSell:
+		return bid
+	case SideBuy:
+		return ask
+	}
+
+	return decimal.NewFromInt(1)
+}
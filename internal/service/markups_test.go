@@ -0,0 +1,127 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBidAsk(t *testing.T) {
+	table := newMarkupTable([]Markup{
+		{Scope: MarkupPair, Target: "usd/eur", Bps: 10},
+		{Scope: MarkupCurrency, Target: "BTC", Bps: 150},
+		{Scope: MarkupType, Target: "CRYPTO", Bps: 100},
+		{Scope: MarkupType, Target: "FIAT", Bps: 20},
+		{Scope: "UNKNOWN", Target: "USD/GBP", Bps: 5000},
+	})
+
+	usd := Currency{Code: USD, Type: Fiat}
+	eur := Currency{Code: "EUR", Type: Fiat}
+	gbp := Currency{Code: "GBP", Type: Fiat}
+	btc := Currency{Code: "BTC", Type: Crypto}
+	eth := Currency{Code: "ETH", Type: Crypto}
+
+	tests := []struct {
+		name    string
+		table   *markupTable
+		pair    CurrencyPair
+		wantBid string
+		wantAsk string
+	}{
+		{
+			name:    "pair markup",
+			table:   table,
+			pair:    CurrencyPair{Base: usd, Quote: eur},
+			wantBid: "0.999",
+			wantAsk: "1.001",
+		},
+		{
+			name:    "pair markup in reverse direction",
+			table:   table,
+			pair:    CurrencyPair{Base: eur, Quote: usd},
+			wantBid: "0.999",
+			wantAsk: "1.001",
+		},
+		{
+			name:    "currency markup over its type",
+			table:   table,
+			pair:    CurrencyPair{Base: usd, Quote: btc},
+			wantBid: "0.985",
+			wantAsk: "1.015",
+		},
+		{
+			name:    "larger markup of the currencies",
+			table:   table,
+			pair:    CurrencyPair{Base: eth, Quote: gbp},
+			wantBid: "0.99",
+			wantAsk: "1.01",
+		},
+		{
+			name:    "type markup",
+			table:   table,
+			pair:    CurrencyPair{Base: usd, Quote: gbp},
+			wantBid: "0.998",
+			wantAsk: "1.002",
+		},
+		{
+			name:    "no markups",
+			table:   newMarkupTable(nil),
+			pair:    CurrencyPair{Base: usd, Quote: btc},
+			wantBid: "1",
+			wantAsk: "1",
+		},
+		{
+			name:    "markups are not loaded",
+			pair:    CurrencyPair{Base: usd, Quote: btc},
+			wantBid: "1",
+			wantAsk: "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bid, ask := tt.table.bidAsk(&tt.pair, decimal.NewFromInt(1))
+			if !bid.Equal(decimal.RequireFromString(tt.wantBid)) || !ask.Equal(decimal.RequireFromString(tt.wantAsk)) {
+				t.Errorf("bidAsk() = %s/%s, want %s/%s", bid, ask, tt.wantBid, tt.wantAsk)
+			}
+
+			wantSides := map[Side]string{SideMid: "1", SideSell: tt.wantBid, SideBuy: tt.wantAsk}
+			for side, want := range wantSides {
+				if got := tt.table.sideFactor(&tt.pair, side); !got.Equal(decimal.RequireFromString(want)) {
+					t.Errorf("sideFactor(%s) = %s, want %s", side, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSide(t *testing.T) {
+	tests := []struct {
+		side    string
+		want    Side
+		wantErr bool
+	}{
+		{side: "", want: SideMid},
+		{side: "mid", want: SideMid},
+		{side: "Sell", want: SideSell},
+		{side: "BUY", want: SideBuy},
+		{side: "bid", wantErr: true},
+		{side: " sell", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.side, func(t *testing.T) {
+			got, err := ParseSide(tt.side)
+
+			var unknownSide *ErrUnknownSide
+			if tt.wantErr != errors.As(err, &unknownSide) {
+				t.Fatalf("ParseSide() = %v, want error %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSide() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,41 +2,168 @@ package service
 
 import (
 	"blum-test/common/models"
+	"blum-test/internal/repository"
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 )
 
+// listenCurrencyUpdates applies the currency events to the cache. While
+// the subscription is lost the currencies are resynced from the repository
+// every CurrencyPollingInterval and the subscription is retried, so the
+// service keeps serving during the database outage
 func (c *RateCalculator) listenCurrencyUpdates(ctx context.Context) error {
 	notificationChan, err := c.repo.SubscribeToCurrencyUpdates(ctx)
 	if err != nil {
-		return fmt.Errorf("error subscribing to currency updates: %w", err)
+		log.Error("could not subscribe to currency updates, polling currencies", slog.Any("error", err))
 	}
 
+	resyncTicker := time.NewTicker(c.CurrencyPollingInterval)
+	defer resyncTicker.Stop()
+	if notificationChan != nil {
+		resyncTicker.Stop()
+	}
+
+	// enabled currencies which rates are fetched when debounce fires,
+	// debounce is reset on every update until the max wait of the
+	// first pending update
+	pending := make(map[models.CurrencyCode]models.Currency)
+	var pendingSince time.Time
+	debounce := time.NewTimer(c.CurrencyUpdateDebounce)
+	stopTimer(debounce)
+	defer debounce.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info("finishing polling currency updates")
 			return nil
 
-		case notification := <-notificationChan:
+		case <-debounce.C:
+			currencies := pending
+			pending = make(map[models.CurrencyCode]models.Currency)
+			if len(currencies) == 0 {
+				continue
+			}
+
+			if _, err := c.fetchRates(ctx, currencies); err != nil {
+				log.Error("could not fetch rates of updated currencies", slog.Any("error", err))
+			}
+
+		case <-resyncTicker.C:
+			notificationChan, err = c.repo.SubscribeToCurrencyUpdates(ctx)
+			if err != nil {
+				log.Warn("could not restore currency updates subscription", slog.Any("error", err))
+			} else {
+				log.Info("currency updates subscription restored")
+				resyncTicker.Stop()
+			}
+
+			if err := c.resyncCurrencies(ctx); err != nil {
+				log.Error("could not resync currencies", slog.Any("error", err))
+			}
+
+		case notification, ok := <-notificationChan:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				log.Error(
+					"currency updates subscription is lost, polling currencies",
+					slog.Duration("interval", c.CurrencyPollingInterval),
+				)
+				notificationChan = nil
+				resyncTicker.Reset(c.CurrencyPollingInterval)
+				continue
+			}
+
+			if notification.Operation == repository.OperationResync {
+				if err := c.resyncCurrencies(ctx); err != nil {
+					log.Error("could not resync currencies", slog.Any("error", err))
+				}
+				continue
+			}
+
 			currency := models.Currency{
-				Name:      notification.Currency.Name,
-				Code:      models.CurrencyCode(notification.Currency.Code),
-				Type:      notification.Currency.CurrencyType,
-				IsEnabled: notification.Currency.IsEnabled,
+				Name:       notification.Currency.Name,
+				Code:       models.CurrencyCode(notification.Currency.Code),
+				Type:       notification.Currency.CurrencyType,
+				MinorUnits: notification.Currency.MinorUnits,
+				IsEnabled:  notification.Currency.IsEnabled,
 			}
 
 			if notification.Operation == "DELETE" {
 				currency.IsEnabled = false
 			}
 
-			updatedCurrencies := make(map[models.CurrencyCode]models.Currency)
-			c.currencies.Range(func(key models.CurrencyCode, value models.Currency) bool {
-				updatedCurrencies[key] = value
-				return true
-			})
-			c.fetchRates(ctx, updatedCurrencies)
 			c.updateCurrencies([]models.Currency{currency})
+
+			if !currency.IsEnabled {
+				delete(pending, currency.Code)
+				continue
+			}
+
+			now := time.Now()
+			if len(pending) == 0 {
+				pendingSince = now
+			}
+			pending[currency.Code] = currency
+
+			stopTimer(debounce)
+			debounce.Reset(c.debounceDelay(pendingSince, now))
 		}
 	}
 }
+
+// debounceDelay returns the quiet period before fetching the pending
+// updates, not exceeding the max wait of the first of them
+func (c *RateCalculator) debounceDelay(pendingSince, now time.Time) time.Duration {
+	delay := c.CurrencyUpdateDebounce
+	if c.CurrencyUpdateMaxWait > 0 {
+		delay = min(delay, c.CurrencyUpdateMaxWait-now.Sub(pendingSince))
+	}
+
+	return max(delay, 0)
+}
+
+// stopTimer stops the timer and drains its channel, so the timer
+// could be reset without the stale fire
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
+
+// resyncCurrencies replaces the cached currencies with the enabled ones
+// from the repository, covering the events missed while disconnected
+func (c *RateCalculator) resyncCurrencies(ctx context.Context) error {
+	currencies, err := c.repo.ListEnabledCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("ListEnabledCurrencies(): %w", err)
+	}
+
+	enabled := make(map[models.CurrencyCode]models.Currency, len(currencies))
+	for _, currency := range currencies {
+		enabled[currency.Code] = currency
+	}
+
+	disabled := []models.Currency{}
+	c.currencies.Range(func(code models.CurrencyCode, currency models.Currency) bool {
+		if _, ok := enabled[code]; !ok {
+			currency.IsEnabled = false
+			disabled = append(disabled, currency)
+		}
+		return true
+	})
+
+	c.updateCurrencies(append(currencies, disabled...))
+
+	log.Info("currencies resynced", slog.Int("enabled", len(enabled)), slog.Int("disabled", len(disabled)))
+
+	_, err = c.fetchRates(ctx, enabled)
+	return err
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"blum-test/common/config"
+	. "blum-test/common/models"
+	"blum-test/internal/repository"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errDatabaseDown = errors.New("database is down")
+
+// fakeCurrencyRepo serves the subscriptions in order, the subscription
+// fails on nil channel and when they are over
+type fakeCurrencyRepo struct {
+	repository.ICurrencyRepository
+
+	mu            sync.Mutex
+	currencies    []Currency
+	subscriptions []chan repository.CurrencyNotification
+	subscribed    int
+	listed        int
+}
+
+func (r *fakeCurrencyRepo) ListEnabledCurrencies(ctx context.Context) ([]Currency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.listed++
+	return append([]Currency{}, r.currencies...), nil
+}
+
+func (r *fakeCurrencyRepo) SubscribeToCurrencyUpdates(ctx context.Context) (<-chan repository.CurrencyNotification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscribed++
+	if len(r.subscriptions) == 0 {
+		return nil, errDatabaseDown
+	}
+
+	res := r.subscriptions[0]
+	r.subscriptions = r.subscriptions[1:]
+	if res == nil {
+		return nil, errDatabaseDown
+	}
+	return res, nil
+}
+
+func (r *fakeCurrencyRepo) calls() (subscribed int, listed int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.subscribed, r.listed
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestListenCurrencyUpdatesSurvivesLostSubscription(t *testing.T) {
+	lost := make(chan repository.CurrencyNotification)
+	restored := make(chan repository.CurrencyNotification)
+
+	repo := &fakeCurrencyRepo{
+		currencies: []Currency{{Code: "EUR", Type: Fiat, IsEnabled: true}},
+		// the subscription is restored on the second poll
+		subscriptions: []chan repository.CurrencyNotification{lost, nil, restored},
+	}
+
+	c := &RateCalculator{
+		Service: config.Service{
+			CurrencyPollingInterval: 5 * time.Millisecond,
+			CurrencyUpdateDebounce:  time.Millisecond,
+		},
+		repo: repo,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- c.listenCurrencyUpdates(ctx)
+	}()
+
+	close(lost)
+
+	// currencies are polled until the subscription is restored
+	waitFor(t, func() bool {
+		subscribed, listed := repo.calls()
+		return subscribed >= 3 && listed >= 2
+	})
+
+	if _, ok := c.currencies.Load("EUR"); !ok {
+		t.Error("currencies are not resynced while subscription is lost")
+	}
+
+	// the restored subscription delivers the events
+	notification := repository.CurrencyNotification{Operation: "DELETE"}
+	notification.Currency.Code = "EUR"
+	restored <- notification
+
+	waitFor(t, func() bool {
+		_, ok := c.currencies.Load("EUR")
+		return !ok
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	if subscribed, _ := repo.calls(); subscribed != 3 {
+		t.Errorf("subscribed %d times, want polling to stop after restore", subscribed)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("listenCurrencyUpdates() = %v, want nil", err)
+	}
+}
+
+func TestListenCurrencyUpdatesWithoutInitialSubscription(t *testing.T) {
+	repo := &fakeCurrencyRepo{
+		currencies: []Currency{{Code: "EUR", Type: Fiat, IsEnabled: true}},
+	}
+
+	c := &RateCalculator{
+		Service: config.Service{
+			CurrencyPollingInterval: 5 * time.Millisecond,
+		},
+		repo: repo,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- c.listenCurrencyUpdates(ctx)
+	}()
+
+	waitFor(t, func() bool {
+		_, listed := repo.calls()
+		return listed >= 2
+	})
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("listenCurrencyUpdates() = %v, want nil", err)
+	}
+}
+
+func TestDebounceDelay(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		maxWait time.Duration
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{name: "first update", maxWait: 5 * time.Second, want: 500 * time.Millisecond},
+		{name: "quiet period before max wait", maxWait: 5 * time.Second, elapsed: 4 * time.Second, want: 500 * time.Millisecond},
+		{name: "capped by max wait", maxWait: 5 * time.Second, elapsed: 4800 * time.Millisecond, want: 200 * time.Millisecond},
+		{name: "max wait is over", maxWait: 5 * time.Second, elapsed: 6 * time.Second, want: 0},
+		{name: "no max wait", elapsed: time.Minute, want: 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RateCalculator{
+				Service: config.Service{
+					CurrencyUpdateDebounce: 500 * time.Millisecond,
+					CurrencyUpdateMaxWait:  tt.maxWait,
+				},
+			}
+
+			if got := c.debounceDelay(since, since.Add(tt.elapsed)); got != tt.want {
+				t.Errorf("debounceDelay() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,31 +2,105 @@ package service
 
 import (
 	"blum-test/common/models"
+	"blum-test/internal/clients"
 	"context"
-	"fmt"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
 	"time"
 )
 
-func (c *RateCalculator) pollRates(ctx context.Context) error {
-	ticker := time.NewTicker(c.RatePollingInterval)
-	defer ticker.Stop()
+// pollRates fetches rates every polling interval, failed fetches are
+// retried with exponential backoff and jitter, the delay requested by
+// the rate limited provider is respected. Partial updates are not
+// retried, the missing rates are requested with the next poll. The failed
+// initial fetch is retried as the first failure
+func (c *RateCalculator) pollRates(ctx context.Context, initialErr error) error {
+	failures := 0
+	delay := c.RatePollingInterval
+	if initialErr != nil {
+		failures = 1
+		delay = c.retryDelay(failures, initialErr)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info("finishing rate polling")
 			return nil
-		case <-ticker.C:
+		case <-timer.C:
+			if err := c.loadMarkups(ctx); err != nil {
+				log.Error("could not reload markups", slog.Any("error", err))
+			}
+			if c.feesPolled.Load() {
+				if err := c.loadFees(ctx); err != nil {
+					log.Error("could not reload fee rules", slog.Any("error", err))
+				}
+			}
+
 			currencies := make(map[models.CurrencyCode]models.Currency)
 			c.currencies.Range(func(key models.CurrencyCode, value models.Currency) bool {
 				currencies[key] = value
 				return true
 			})
-			if err := c.fetchRates(ctx, currencies); err != nil {
-				retErr := fmt.Errorf("error while fetching rates: %w", err)
-				log.Error("error polling rates", retErr)
-				return retErr
+
+			updated, err := c.fetchRates(ctx, currencies)
+
+			var rateLimited *clients.ErrRateLimited
+			if err == nil || (updated > 0 && !errors.As(err, &rateLimited)) {
+				if err != nil {
+					log.Warn(
+						"rates are partially updated",
+						slog.Int("updated", updated),
+						slog.Any("error", err),
+					)
+				}
+				if failures > 0 {
+					log.Info("rates polling recovered", slog.Int("consecutive_failures", failures))
+				}
+				failures = 0
+				timer.Reset(c.RatePollingInterval)
+				continue
 			}
+
+			failures++
+			delay := c.retryDelay(failures, err)
+			log.Error(
+				"error polling rates",
+				slog.Int("consecutive_failures", failures),
+				slog.Duration("retry_in", delay),
+				slog.Any("error", err),
+			)
+			timer.Reset(delay)
 		}
 	}
 }
+
+// retryDelay returns the delay before the next attempt after the
+// failures in a row, but not less than requested by rate limit.
+// The backoff is capped by the polling interval, so the retries
+// are not more rare than the regular polls
+func (c *RateCalculator) retryDelay(failures int, err error) time.Duration {
+	maxDelay := min(c.RateRetryMaxBackoff, c.RatePollingInterval)
+
+	delay := c.RateRetryMinBackoff
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+
+	// equal jitter keeps at least half of the delay
+	if delay > 1 {
+		delay = delay/2 + rand.N(delay/2)
+	}
+
+	var rateLimited *clients.ErrRateLimited
+	if errors.As(err, &rateLimited) && rateLimited.RetryAfter > delay {
+		delay = rateLimited.RetryAfter
+	}
+
+	return delay
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"blum-test/common/config"
+	. "blum-test/common/models"
+	"blum-test/internal/clients"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeMarkupRepo counts the polls, markups are reloaded on every poll
+type fakeMarkupRepo struct {
+	listed atomic.Int32
+}
+
+func (r *fakeMarkupRepo) ListMarkups(ctx context.Context) ([]Markup, error) {
+	r.listed.Add(1)
+	return nil, nil
+}
+
+func TestPollRatesRetriesFailedInitialFetch(t *testing.T) {
+	tests := []struct {
+		name       string
+		initialErr error
+		wantPolled bool
+	}{
+		{name: "initial fetch failed", initialErr: errProviderDown, wantPolled: true},
+		{name: "initial fetch succeeded", wantPolled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeMarkupRepo{}
+			c := &RateCalculator{
+				Service: config.Service{
+					RatePollingInterval: time.Hour,
+					RateRetryMinBackoff: 2 * time.Millisecond,
+					RateRetryMaxBackoff: 10 * time.Millisecond,
+				},
+				markupRepo: repo,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				done <- c.pollRates(ctx, tt.initialErr)
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("pollRates() = %v, want nil", err)
+			}
+
+			if polled := repo.listed.Load() > 0; polled != tt.wantPolled {
+				t.Errorf("polled = %t, want %t before the polling interval", polled, tt.wantPolled)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	c := &RateCalculator{
+		Service: config.Service{
+			RatePollingInterval: time.Minute,
+			RateRetryMinBackoff: time.Second,
+			RateRetryMaxBackoff: 2 * time.Minute,
+		},
+	}
+
+	rateLimited := func(retryAfter time.Duration) error {
+		return &clients.ErrRateLimited{
+			Provider:   "primary",
+			RetryAfter: retryAfter,
+			Err:        errProviderDown,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		failures int
+		err      error
+		// the delay is jittered within [wantMin, wantMax)
+		wantMin, wantMax time.Duration
+	}{
+		{name: "first failure", failures: 1, err: errProviderDown, wantMin: 500 * time.Millisecond, wantMax: time.Second},
+		{name: "backoff doubles", failures: 3, err: errProviderDown, wantMin: 2 * time.Second, wantMax: 4 * time.Second},
+		{name: "capped by polling interval", failures: 10, err: errProviderDown, wantMin: 30 * time.Second, wantMax: time.Minute},
+		{name: "many failures", failures: 1000, err: errProviderDown, wantMin: 30 * time.Second, wantMax: time.Minute},
+		{name: "retry after overrides backoff", failures: 1, err: rateLimited(10 * time.Second), wantMin: 10 * time.Second, wantMax: 10*time.Second + 1},
+		{name: "retry after above interval", failures: 1, err: rateLimited(5 * time.Minute), wantMin: 5 * time.Minute, wantMax: 5*time.Minute + 1},
+		{name: "shorter retry after keeps backoff", failures: 3, err: rateLimited(time.Millisecond), wantMin: 2 * time.Second, wantMax: 4 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// jitter is random, so the bounds are checked on many samples
+			for i := 0; i < 100; i++ {
+				got := c.retryDelay(tt.failures, tt.err)
+				if got < tt.wantMin || got >= tt.wantMax {
+					t.Fatalf("retryDelay(%d) = %s, want in [%s, %s)", tt.failures, got, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryDelayIsCappedByMaxBackoff(t *testing.T) {
+	c := &RateCalculator{
+		Service: config.Service{
+			RatePollingInterval: time.Hour,
+			RateRetryMinBackoff: time.Second,
+			RateRetryMaxBackoff: 8 * time.Second,
+		},
+	}
+
+	for i := 0; i < 100; i++ {
+		if got := c.retryDelay(20, errProviderDown); got < 4*time.Second || got >= 8*time.Second {
+			t.Fatalf("retryDelay() = %s, want in [4s, 8s)", got)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"blum-test/internal/repository"
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var quoteIDRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// QuoteConversion is the conversion by the locked rate of the quote
+type QuoteConversion struct {
+	// Output is the net amount after the fee
+	Output   decimal.Decimal
+	Charge   Charge
+	Decimals int32
+	Quote    Quote
+}
+
+// CreateQuote locks the current rate of the side and the fee schedules of
+// the pair for QuoteTTL, empty side locks the mid rate. The quote is
+// persisted so it could be executed by any instance
+func (c *RateCalculator) CreateQuote(ctx context.Context, base, quote string, side Side) (*Quote, error) {
+	side, err := ParseSide(string(side))
+	if err != nil {
+		return nil, err
+	}
+
+	pair, _, err := c.validateRequest(ConvertRequest{
+		Base:  base,
+		Quote: quote,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	prices := c.currentPricing()
+
+	rate, _, err := c.crossRate(prices.graph, pair.Base.Code, pair.Quote.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkRateAge(pair, rate, now); err != nil {
+		return nil, err
+	}
+
+	price := rate.Value.Mul(prices.markups.sideFactor(pair, side))
+
+	res, err := c.quoteRepo.CreateQuote(ctx, Quote{
+		Base:      pair.Base.Code,
+		Quote:     pair.Quote.Code,
+		Side:      string(side),
+		Rate:      price,
+		Fees:      prices.fees.schedules(pair),
+		CreatedAt: now,
+		ExpiresAt: now.Add(c.QuoteTTL),
+	})
+	if err != nil {
+		log.Error("could not create quote", slog.Any("error", err))
+		return nil, ErrServiceInternal
+	}
+
+	return res, nil
+}
+
+// ExecuteQuote converts the amount by the locked rate of the quote,
+// base and quote currencies of the request are taken from the quote.
+// The quote could be executed any number of times until it expires
+func (c *RateCalculator) ExecuteQuote(
+	ctx context.Context,
+	id string,
+	req ConvertRequest,
+) (*QuoteConversion, error) {
+	if !quoteIDRe.MatchString(id) {
+		return nil, repository.ErrQuoteNotFound
+	}
+
+	if err := ValidateAmount(req.Amount); err != nil {
+		return nil, err
+	}
+
+	quote, err := c.quoteRepo.GetQuote(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetQuote(): %w", err)
+	}
+
+	now := time.Now()
+	if quote.IsExpired(now) {
+		return nil, &ErrQuoteExpired{
+			ID:        quote.ID,
+			ExpiresAt: quote.ExpiresAt,
+		}
+	}
+
+	req.Base = string(quote.Base)
+	req.Quote = string(quote.Quote)
+
+	_, decimals, err := c.validateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// fees are charged by the schedules locked with the quote
+	charge := newCharge(quote.Fees, quote.Rate.Mul(req.Amount), decimals, req.Rounding)
+
+	return &QuoteConversion{
+		Output:   charge.Net,
+		Charge:   charge,
+		Decimals: decimals,
+		Quote:    *quote,
+	}, nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"blum-test/common/config"
+	. "blum-test/common/models"
+	"blum-test/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// fakeQuoteRepo keeps the quotes in memory with the sequential IDs
+type fakeQuoteRepo struct {
+	quotes map[string]Quote
+}
+
+func (r *fakeQuoteRepo) CreateQuote(ctx context.Context, quote Quote) (*Quote, error) {
+	quote.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(r.quotes)+1)
+	r.quotes[quote.ID] = quote
+
+	return &quote, nil
+}
+
+func (r *fakeQuoteRepo) GetQuote(ctx context.Context, id string) (*Quote, error) {
+	quote, ok := r.quotes[id]
+	if !ok {
+		return nil, repository.ErrQuoteNotFound
+	}
+
+	return &quote, nil
+}
+
+// newQuoteCalculator prices USD/EUR at 0.9 with 100 bps markup
+// and 1% fee of the pair
+func newQuoteCalculator() *RateCalculator {
+	c := &RateCalculator{
+		Service: config.Service{
+			QuoteTTL: time.Minute,
+		},
+		quoteRepo: &fakeQuoteRepo{quotes: map[string]Quote{}},
+		pivots:    []CurrencyCode{USD},
+	}
+
+	c.updateCurrencies([]Currency{
+		{Code: USD, Type: Fiat, MinorUnits: 2, IsEnabled: true},
+		{Code: "EUR", Type: Fiat, MinorUnits: 2, IsEnabled: true},
+	})
+
+	graph := newRateGraph([]Rate{newQuote(USD, "EUR", "0.9")})
+	c.graph.Store(&graph)
+	c.markups.Store(newMarkupTable([]Markup{{Scope: MarkupPair, Target: "USD/EUR", Bps: 100}}))
+	c.fees.Store(newFeeTable([]FeeRule{newFeeRule(FeePair, "USD/EUR", "0", "0", 100, "0", "")}))
+
+	return c
+}
+
+func TestCreateQuote(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     Side
+		wantSide Side
+		wantRate string
+		wantErr  error
+	}{
+		{name: "sell locks bid", side: SideSell, wantSide: SideSell, wantRate: "0.891"},
+		{name: "buy locks ask", side: SideBuy, wantSide: SideBuy, wantRate: "0.909"},
+		{name: "mid", side: SideMid, wantSide: SideMid, wantRate: "0.9"},
+		{name: "omitted side is mid", side: "", wantSide: SideMid, wantRate: "0.9"},
+		{name: "side is case insensitive", side: "BUY", wantSide: SideBuy, wantRate: "0.909"},
+		{name: "invalid side", side: "bid", wantErr: &ErrUnknownSide{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newQuoteCalculator()
+
+			quote, err := c.CreateQuote(context.Background(), "usd", "eur", tt.side)
+			if tt.wantErr != nil {
+				var unknownSide *ErrUnknownSide
+				if !errors.As(err, &unknownSide) {
+					t.Fatalf("CreateQuote() = %v, want ErrUnknownSide", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateQuote(): %v", err)
+			}
+
+			if quote.Side != string(tt.wantSide) {
+				t.Errorf("side = %s, want %s", quote.Side, tt.wantSide)
+			}
+			if !quote.Rate.Equal(decimal.RequireFromString(tt.wantRate)) {
+				t.Errorf("rate = %s, want %s", quote.Rate, tt.wantRate)
+			}
+			if quote.Base != USD || quote.Quote != "EUR" {
+				t.Errorf("pair = %s/%s, want USD/EUR", quote.Base, quote.Quote)
+			}
+			if got := quote.ExpiresAt.Sub(quote.CreatedAt); got != time.Minute {
+				t.Errorf("quote TTL = %s, want 1m", got)
+			}
+			if len(quote.Fees) != 1 {
+				t.Errorf("fees = %v, want the pair schedule locked", quote.Fees)
+			}
+		})
+	}
+}
+
+func TestCreateQuoteUnknownCurrency(t *testing.T) {
+	c := newQuoteCalculator()
+
+	_, err := c.CreateQuote(context.Background(), "USD", "JPY", SideSell)
+
+	var notAvailable *ErrCurrencyNotAvailable
+	if !errors.As(err, &notAvailable) {
+		t.Errorf("CreateQuote() = %v, want ErrCurrencyNotAvailable", err)
+	}
+}
+
+func TestExecuteQuote(t *testing.T) {
+	c := newQuoteCalculator()
+
+	quote, err := c.CreateQuote(context.Background(), "USD", "EUR", SideSell)
+	if err != nil {
+		t.Fatalf("CreateQuote(): %v", err)
+	}
+
+	// rates and fees changed after the quote was created
+	graph := newRateGraph([]Rate{newQuote(USD, "EUR", "0.5")})
+	c.graph.Store(&graph)
+	c.fees.Store(newFeeTable(nil))
+
+	req := ConvertRequest{
+		Amount:   decimal.NewFromInt(1000),
+		Rounding: RoundHalfUp,
+	}
+
+	// the quote is executed by the locked rate and fees any number
+	// of times until it expires
+	for i := 0; i < 2; i++ {
+		res, err := c.ExecuteQuote(context.Background(), quote.ID, req)
+		if err != nil {
+			t.Fatalf("ExecuteQuote() #%d: %v", i, err)
+		}
+
+		want := newTestCharge("891", "8.91", "882.09")
+		if !res.Charge.Gross.Equal(want.Gross) || !res.Charge.Fee.Equal(want.Fee) || !res.Output.Equal(want.Net) {
+			t.Errorf("ExecuteQuote() #%d = %v/%v/%v, want %v/%v/%v", i,
+				res.Charge.Gross, res.Charge.Fee, res.Output, want.Gross, want.Fee, want.Net)
+		}
+		if res.Decimals != 2 {
+			t.Errorf("decimals = %d, want quote currency minor units", res.Decimals)
+		}
+	}
+}
+
+func TestExecuteQuoteErrors(t *testing.T) {
+	c := newQuoteCalculator()
+
+	quote, err := c.CreateQuote(context.Background(), "USD", "EUR", SideBuy)
+	if err != nil {
+		t.Fatalf("CreateQuote(): %v", err)
+	}
+
+	repo := c.quoteRepo.(*fakeQuoteRepo)
+	expired := repo.quotes[quote.ID]
+	expired.ID = "00000000-0000-0000-0000-000000000099"
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	repo.quotes[expired.ID] = expired
+
+	tooManyDecimals := int64(defaultMaxDecimals) + 1
+
+	tests := []struct {
+		name   string
+		id     string
+		amount string
+		// decimals is quote currency minor units if nil
+		decimals *int64
+		check    func(err error) bool
+	}{
+		{
+			name:   "malformed id",
+			id:     "1",
+			amount: "10",
+			check:  func(err error) bool { return errors.Is(err, repository.ErrQuoteNotFound) },
+		},
+		{
+			name:   "unknown id",
+			id:     "00000000-0000-0000-0000-000000000042",
+			amount: "10",
+			check:  func(err error) bool { return errors.Is(err, repository.ErrQuoteNotFound) },
+		},
+		{
+			name:   "expired",
+			id:     expired.ID,
+			amount: "10",
+			check: func(err error) bool {
+				var quoteExpired *ErrQuoteExpired
+				return errors.As(err, &quoteExpired)
+			},
+		},
+		{
+			name:   "zero amount",
+			id:     quote.ID,
+			amount: "0",
+			check: func(err error) bool {
+				var invalidAmount *ErrInvalidAmount
+				return errors.As(err, &invalidAmount)
+			},
+		},
+		{
+			name:   "negative amount",
+			id:     quote.ID,
+			amount: "-10",
+			check: func(err error) bool {
+				var invalidAmount *ErrInvalidAmount
+				return errors.As(err, &invalidAmount)
+			},
+		},
+		{
+			name:     "too many decimals",
+			id:       quote.ID,
+			amount:   "10",
+			decimals: &tooManyDecimals,
+			check: func(err error) bool {
+				var invalidDecimals *ErrInvalidDecimals
+				return errors.As(err, &invalidDecimals)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.ExecuteQuote(context.Background(), tt.id, ConvertRequest{
+				Amount:   decimal.RequireFromString(tt.amount),
+				Decimals: tt.decimals,
+				Rounding: RoundHalfUp,
+			})
+			if !tt.check(err) {
+				t.Errorf("ExecuteQuote() = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rate is the quote of the currency pair: 1 Base = Value Quote
+type Rate struct {
+	Base  CurrencyCode
+	Quote CurrencyCode
+	Value decimal.Decimal
+	// Providers are names of the providers which supplied the rate
+	Providers []string
+	// Spread is the relative difference between the highest and
+	// the lowest rates of the providers, zero for single provider
+	Spread decimal.Decimal
+	// Stale is set for the rates restored from the persisted
+	// snapshots until they are fetched from the providers
+	Stale bool
+	// FetchedAt is the fetch time of the rate, the oldest
+	// one of the quotes for cross rates
+	FetchedAt time.Time
+
+	// num and den are the exact rate of inverted and cross rates,
+	// their Value is rounded to ratePrecision decimal places
+	num, den decimal.Decimal
+}
+
+// ratePrecision is the decimal places of the rates calculated by division
+const ratePrecision = 28
+
+// convertGuardDigits are the decimal places over the output ones the
+// converted amount is calculated with before it is rounded
+const convertGuardDigits = 8
+
+func (r Rate) Pair() string {
+	return string(r.Base) + "/" + string(r.Quote)
+}
+
+// fraction returns the exact rate as numerator and denominator
+func (r Rate) fraction() (decimal.Decimal, decimal.Decimal) {
+	if r.den.IsZero() {
+		return r.Value, decimal.NewFromInt(1)
+	}
+
+	return r.num, r.den
+}
+
+// withFraction sets the exact rate num/den, zero den is ignored
+func (r Rate) withFraction(num, den decimal.Decimal) Rate {
+	if den.IsZero() {
+		return r
+	}
+
+	r.num, r.den = num, den
+	r.Value = num.DivRound(den, ratePrecision)
+	return r
+}
+
+// invert returns quote of the reversed pair
+func (r Rate) invert() Rate {
+	inverted := r
+	inverted.Base, inverted.Quote = r.Quote, r.Base
+
+	num, den := r.fraction()
+	return inverted.withFraction(den, num)
+}
+
+// convert returns the amount in the quote currency by the exact rate,
+// the division is the last so its error is not multiplied by the amount
+func (r Rate) convert(amount decimal.Decimal, decimals int32) decimal.Decimal {
+	num, den := r.fraction()
+
+	return amount.Mul(num).DivRound(den, decimals+convertGuardDigits)
+}
+
+// combineRates returns cross rate of the path of the rates, where
+// quote currency of each rate is the base currency of the next one
+func combineRates(base, quote CurrencyCode, path []Rate) Rate {
+	res := Rate{
+		Base:      base,
+		Quote:     quote,
+		Providers: []string{},
+		Spread:    decimal.Zero,
+	}
+
+	num, den := decimal.NewFromInt(1), decimal.NewFromInt(1)
+
+	seen := make(map[string]struct{})
+	for _, rate := range path {
+		rateNum, rateDen := rate.fraction()
+		num, den = num.Mul(rateNum), den.Mul(rateDen)
+		res.Spread = res.Spread.Add(rate.Spread)
+		res.Stale = res.Stale || rate.Stale
+		if !rate.FetchedAt.IsZero() &&
+			(res.FetchedAt.IsZero() || rate.FetchedAt.Before(res.FetchedAt)) {
+			res.FetchedAt = rate.FetchedAt
+		}
+
+		for _, provider := range rate.Providers {
+			if _, ok := seen[provider]; !ok {
+				seen[provider] = struct{}{}
+				res.Providers = append(res.Providers, provider)
+			}
+		}
+	}
+
+	if den.Equal(decimal.NewFromInt(1)) {
+		res.Value = num
+		return res
+	}
+
+	return res.withFraction(num, den)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCrossRateConvertPrecision(t *testing.T) {
+	graph := newRateGraph([]Rate{
+		newQuote(USD, USDT, "1.0002"),
+		newQuote("ETH", USDT, "3572.14"),
+	})
+
+	path, ok := graph.findPath(USD, "ETH", []CurrencyCode{USD, USDT})
+	if !ok {
+		t.Fatal("no path from USD to ETH")
+	}
+	rate := combineRates(USD, "ETH", path)
+
+	tests := []struct {
+		amount   string
+		decimals int32
+		want     string
+	}{
+		{amount: "1000000", decimals: 18, want: "280.000223955388086693"},
+		{amount: "1", decimals: 18, want: "0.000280000223955388"},
+		{amount: "123456789012.5", decimals: 18, want: "34567928.572313095231429899"},
+		{amount: "1000000", decimals: 2, want: "280"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount, func(t *testing.T) {
+			got := RoundHalfUp.round(rate.convert(decimal.RequireFromString(tt.amount), tt.decimals), tt.decimals)
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("convert(%s) = %s, want %s", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvertKeepsExactRate(t *testing.T) {
+	rate := newQuote(USD, "EUR", "0.92")
+
+	inverted := rate.invert()
+	if want := decimal.RequireFromString("1.0869565217391304347826086957"); !inverted.Value.Equal(want) {
+		t.Errorf("inverted value = %s, want %s", inverted.Value, want)
+	}
+
+	// double inversion restores the quote exactly
+	if restored := inverted.invert(); !restored.Value.Equal(rate.Value) {
+		t.Errorf("double inverted value = %s, want %s", restored.Value, rate.Value)
+	}
+
+	cross := combineRates("EUR", "CNY", []Rate{inverted, newQuote(USD, "CNY", "7")})
+	if got := cross.convert(decimal.NewFromInt(92), 2); !got.Equal(decimal.NewFromInt(700)) {
+		t.Errorf("92 EUR = %s CNY, want 700", got)
+	}
+}
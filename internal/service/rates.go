@@ -0,0 +1,69 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"sort"
+)
+
+// Rates returns rates of the symbols against the base currency calculated
+// through rates in USD. All enabled currencies are used if symbols are empty,
+// symbols without rates or forbidden for conversion are skipped.
+func (c *RateCalculator) Rates(base string, symbols []string) ([]Rate, error) {
+	baseCurrency, err := c.getCurrency(base)
+	if err != nil {
+		return nil, err
+	}
+
+	baseRate, ok := c.ratesInUSD.Load(baseCurrency.Code)
+	if !ok {
+		return nil, &ErrRateIsNotAvailable{
+			Code: baseCurrency.Code,
+		}
+	}
+
+	currencies := []Currency{}
+	if len(symbols) == 0 {
+		c.currencies.Range(func(_ CurrencyCode, currency Currency) bool {
+			currencies = append(currencies, currency)
+			return true
+		})
+	}
+	for _, symbol := range symbols {
+		currency, err := c.getCurrency(symbol)
+		if err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, *currency)
+	}
+
+	res := make([]Rate, 0, len(currencies))
+	seen := make(map[CurrencyCode]struct{}, len(currencies))
+	for _, currency := range currencies {
+		if _, ok := seen[currency.Code]; ok {
+			continue
+		}
+		seen[currency.Code] = struct{}{}
+
+		if currency.Code == baseCurrency.Code ||
+			!c.pairTypesPolicy.IsAllowed(baseCurrency.Type, currency.Type) {
+			continue
+		}
+
+		rate, ok := c.ratesInUSD.Load(currency.Code)
+		if !ok {
+			continue
+		}
+
+		res = append(res, combineRates(
+			baseCurrency.Code,
+			currency.Code,
+			[]Rate{baseRate.invert(), *rate},
+		))
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Quote < res[j].Quote
+	})
+
+	return res, nil
+}
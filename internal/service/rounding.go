@@ -0,0 +1,52 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds half away from zero
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven is banker's rounding, half is rounded to the even digit
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundFloor rounds towards negative infinity
+	RoundFloor RoundingMode = "floor"
+	// RoundCeil rounds towards positive infinity
+	RoundCeil RoundingMode = "ceil"
+	// RoundTruncate drops extra digits, i.e. rounds towards zero
+	RoundTruncate RoundingMode = "truncate"
+)
+
+func ParseRoundingMode(mode string) (RoundingMode, error) {
+	if mode == "" {
+		return RoundHalfUp, nil
+	}
+
+	switch res := RoundingMode(strings.ToLower(mode)); res {
+	case RoundHalfUp, RoundHalfEven, RoundFloor, RoundCeil, RoundTruncate:
+		return res, nil
+	}
+
+	return "", &ErrUnknownRoundingMode{
+		Mode: mode,
+	}
+}
+
+func (m RoundingMode) round(value decimal.Decimal, places int32) decimal.Decimal {
+	switch m {
+	case RoundHalfEven:
+		return value.RoundBank(places)
+	case RoundFloor:
+		return value.RoundFloor(places)
+	case RoundCeil:
+		return value.RoundCeil(places)
+	case RoundTruncate:
+		return value.Truncate(places)
+	default:
+		return value.Round(places)
+	}
+}
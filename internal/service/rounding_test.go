@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParseRoundingMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    RoundingMode
+		wantErr bool
+	}{
+		{mode: "", want: RoundHalfUp},
+		{mode: "half_up", want: RoundHalfUp},
+		{mode: "HALF_EVEN", want: RoundHalfEven},
+		{mode: "floor", want: RoundFloor},
+		{mode: "Ceil", want: RoundCeil},
+		{mode: "truncate", want: RoundTruncate},
+		{mode: "half_down", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := ParseRoundingMode(tt.mode)
+			if tt.wantErr {
+				var unknown *ErrUnknownRoundingMode
+				if !errors.As(err, &unknown) {
+					t.Fatalf("error = %v, want ErrUnknownRoundingMode", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRoundingMode(): %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRoundingMode() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	values := []string{"2.345", "2.355", "2.3451", "-2.345", "-2.3451", "2.34"}
+
+	tests := []struct {
+		mode RoundingMode
+		want []string
+	}{
+		{mode: RoundHalfUp, want: []string{"2.35", "2.36", "2.35", "-2.35", "-2.35", "2.34"}},
+		{mode: RoundHalfEven, want: []string{"2.34", "2.36", "2.35", "-2.34", "-2.35", "2.34"}},
+		{mode: RoundFloor, want: []string{"2.34", "2.35", "2.34", "-2.35", "-2.35", "2.34"}},
+		{mode: RoundCeil, want: []string{"2.35", "2.36", "2.35", "-2.34", "-2.34", "2.34"}},
+		{mode: RoundTruncate, want: []string{"2.34", "2.35", "2.34", "-2.34", "-2.34", "2.34"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			for i, value := range values {
+				got := tt.mode.round(decimal.RequireFromString(value), 2)
+				if !got.Equal(decimal.RequireFromString(tt.want[i])) {
+					t.Errorf("round(%s) = %s, want %s", value, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRoundToInteger(t *testing.T) {
+	value := decimal.RequireFromString("2.5")
+
+	tests := []struct {
+		mode RoundingMode
+		want string
+	}{
+		{mode: RoundHalfUp, want: "3"},
+		{mode: RoundHalfEven, want: "2"},
+		{mode: RoundFloor, want: "2"},
+		{mode: RoundCeil, want: "3"},
+		{mode: RoundTruncate, want: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			if got := tt.mode.round(value, 0); got.String() != tt.want {
+				t.Errorf("round(%s) = %s, want %s", value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"log/slog"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// anchorOf returns the currency against which the currency is quoted:
+// configured one, USD for fiat and USDT, USDT for the other crypto
+func (c *RateCalculator) anchorOf(currency Currency) CurrencyCode {
+	if anchor, ok := c.quoteCurrencies[currency.Code]; ok {
+		return anchor
+	}
+
+	if currency.Type == Fiat || currency.Code == USDT || currency.Code == USD {
+		return USD
+	}
+
+	return USDT
+}
+
+// withAnchors adds to the currencies their anchors which are not
+// enabled themselves, so the quotes could be connected to USD
+func (c *RateCalculator) withAnchors(currencies map[CurrencyCode]Currency) map[CurrencyCode]Currency {
+	res := make(map[CurrencyCode]Currency, len(currencies))
+	for code, currency := range currencies {
+		res[code] = currency
+	}
+
+	queue := make([]Currency, 0, len(res))
+	for _, currency := range res {
+		queue = append(queue, currency)
+	}
+
+	for len(queue) > 0 {
+		anchor := c.anchorOf(queue[0])
+		queue = queue[1:]
+
+		if _, ok := res[anchor]; ok || anchor == USD {
+			continue
+		}
+
+		currency, ok := c.currencies.Load(anchor)
+		if !ok {
+			currency = Currency{
+				Code: anchor,
+				Type: Crypto,
+			}
+		}
+
+		res[anchor] = currency
+		queue = append(queue, currency)
+	}
+
+	return res
+}
+
+// rateGraph builds the graph of the current quotes
+func (c *RateCalculator) rateGraph() rateGraph {
+	quotes := []Rate{}
+	c.quotes.Range(func(_ CurrencyCode, rate *Rate) bool {
+		quotes = append(quotes, *rate)
+		return true
+	})
+
+	return newRateGraph(quotes)
+}
+
+// updateRatesInUSD rebuilds the rate graph used for conversions and
+// recalculates rates of the enabled currencies against USD through it
+func (c *RateCalculator) updateRatesInUSD() []Rate {
+	c.graphMu.Lock()
+	defer c.graphMu.Unlock()
+
+	graph := c.rateGraph()
+	c.graph.Store(&graph)
+
+	res := []Rate{}
+	c.currencies.Range(func(code CurrencyCode, _ Currency) bool {
+		path, ok := graph.findPath(USD, code, c.pivots)
+		if !ok {
+			log.Warn("no rate path to USD", slog.String("currency_code", string(code)))
+			return true
+		}
+
+		rate := combineRates(USD, code, path)
+		c.ratesInUSD.Store(code, &rate)
+		res = append(res, rate)
+		return true
+	})
+
+	return res
+}
+
+func parsePivots(pivots []string) []CurrencyCode {
+	res := make([]CurrencyCode, 0, len(pivots))
+	for _, pivot := range pivots {
+		res = append(res, CurrencyCode(strings.ToUpper(strings.TrimSpace(pivot))))
+	}
+
+	return res
+}
+
+func parseQuoteCurrencies(quoteCurrencies map[string]string) map[CurrencyCode]CurrencyCode {
+	res := make(map[CurrencyCode]CurrencyCode, len(quoteCurrencies))
+	for code, anchor := range quoteCurrencies {
+		res[CurrencyCode(strings.ToUpper(code))] = CurrencyCode(strings.ToUpper(anchor))
+	}
+
+	return res
+}
+
+// currentGraph returns the rate graph of the last update, the graph
+// is not changed after the update so it is a consistent snapshot
+func (c *RateCalculator) currentGraph() rateGraph {
+	graph := c.graph.Load()
+	if graph == nil {
+		return rateGraph{}
+	}
+
+	return *graph
+}
+
+// pricing is the consistent snapshot of the rate graph, the markups
+// and the fees which the conversions are calculated by
+type pricing struct {
+	graph   rateGraph
+	markups *markupTable
+	fees    *feeTable
+}
+
+// currentPricing returns the snapshot of the current rate graph,
+// markups and fees, reloads do not affect the snapshot
+func (c *RateCalculator) currentPricing() pricing {
+	return pricing{
+		graph:   c.currentGraph(),
+		markups: c.markups.Load(),
+		fees:    c.fees.Load(),
+	}
+}
+
+// crossRate returns the rate of base currency in quote currency
+// and the path of the quotes of the graph it was calculated by
+func (c *RateCalculator) crossRate(graph rateGraph, base, quote CurrencyCode) (Rate, []Rate, error) {
+	path, ok := graph.findPath(base, quote, c.pivots)
+	if !ok {
+		return Rate{}, nil, &ErrNoConversionPath{
+			Base:  base,
+			Quote: quote,
+		}
+	}
+
+	rate := combineRates(base, quote, path)
+	if rate.Value.Cmp(decimal.Zero) == 0 {
+		log.Error("zero cross rate", slog.String("pair", rate.Pair()))
+		return Rate{}, nil, ErrInvalidInternalRate
+	}
+
+	return rate, path, nil
+}
@@ -5,14 +5,16 @@ import (
 	"blum-test/common/logger"
 	. "blum-test/common/models"
 	"blum-test/common/utils"
-	"blum-test/internal/clients/fastforex"
+	"blum-test/internal/clients"
 	"blum-test/internal/repository"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/shopspring/decimal"
 
@@ -21,7 +23,7 @@ import (
 
 // RateCalculator stores and updates data about currency pairs' rates
 // in order to maintain data relevance. Also can make convertations
-// between two currencies through USD cross-rates.
+// between two currencies through cross-rates of the pivot currencies.
 type RateCalculator struct {
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -41,26 +43,92 @@ type RateCalculator struct {
 	// also mutex synchronization could be used too, though it
 	// could lead to some delayed rates updates, due to its nature
 	currencies utils.MapThSf[CurrencyCode, Currency]
-	// same logic applied here
-	ratesInUSD utils.MapThSf[CurrencyCode, decimal.Decimal]
-
-	repo   repository.ICurrencyRepository
-	client *fastforex.Client
+	// same logic applied here, rates are stored by pointers since Rate
+	// is not comparable, the stored rates are never modified
+	ratesInUSD utils.MapThSf[CurrencyCode, *Rate]
+	// quotes are the edges of the rate graph, the key is the currency
+	// which is priced by the quote against its anchor currency
+	quotes utils.MapThSf[CurrencyCode, *Rate]
+	// graph is rebuilt from quotes after every rates update,
+	// conversions are calculated by it
+	graph atomic.Pointer[rateGraph]
+	// graphMu serializes the graph rebuilds, so the graph of the older
+	// quotes could not overwrite the newer one
+	graphMu sync.Mutex
+	// markups are reloaded from the repository with the rates
+	markups atomic.Pointer[markupTable]
+	// fees are reloaded on every fee rules change
+	fees atomic.Pointer[feeTable]
+	// feesPolled is set when the fee rule events subscription
+	// is lost, fees are reloaded with the rates then
+	feesPolled atomic.Bool
+
+	// pivots are ordered by priority
+	pivots          []CurrencyCode
+	quoteCurrencies map[CurrencyCode]CurrencyCode
+	pairTypesPolicy PairTypesPolicy
+
+	repo       repository.ICurrencyRepository
+	rateRepo   repository.IRateRepository
+	quoteRepo  repository.IQuoteRepository
+	markupRepo repository.IMarkupRepository
+	feeRepo    repository.IFeeRepository
+	// providers are ordered by priority
+	providers []clients.IRateProvider
 }
 
 var log = logger.JSONLogger.With(slog.String("service", "rate_calculator"))
 
+// defaultMaxDecimals is used for currencies without configured limit
+const defaultMaxDecimals int32 = 18
+
 func NewRateCalculator(
 	cfg *config.Service,
 	repo repository.ICurrencyRepository,
-	client *fastforex.Client,
-) *RateCalculator {
+	rateRepo repository.IRateRepository,
+	quoteRepo repository.IQuoteRepository,
+	markupRepo repository.IMarkupRepository,
+	feeRepo repository.IFeeRepository,
+	providers []clients.IRateProvider,
+) (*RateCalculator, error) {
+	switch cfg.RateAggregation {
+	case AggregationFailover, AggregationMedian, AggregationTrimmedMean:
+	default:
+		return nil, &ErrUnknownAggregation{
+			Mode: cfg.RateAggregation,
+		}
+	}
+
+	if cfg.RatePollingInterval <= 0 || cfg.CurrencyPollingInterval <= 0 {
+		return nil, ErrInvalidPollingInterval
+	}
+
+	if cfg.RateRetryMinBackoff <= 0 || cfg.RateRetryMaxBackoff < cfg.RateRetryMinBackoff {
+		return nil, &ErrInvalidRetryBackoff{
+			Min: cfg.RateRetryMinBackoff,
+			Max: cfg.RateRetryMaxBackoff,
+		}
+	}
+
+	pairTypesPolicy, err := NewPairTypesPolicy(cfg.ForbiddenPairTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid forbidden pair types: %w", err)
+	}
+
 	return &RateCalculator{
 		Service: *cfg,
 
-		repo:   repo,
-		client: client,
-	}
+		pivots:          parsePivots(cfg.PivotCurrencies),
+		quoteCurrencies: parseQuoteCurrencies(cfg.QuoteCurrencies),
+		pairTypesPolicy: pairTypesPolicy,
+
+		repo:       repo,
+		rateRepo:   rateRepo,
+		quoteRepo:  quoteRepo,
+		markupRepo: markupRepo,
+		feeRepo:    feeRepo,
+		providers:  providers,
+	}, nil
 }
 
 func (c *RateCalculator) getIsRunning() bool {
@@ -100,11 +168,24 @@ func (c *RateCalculator) Start() error {
 		return c.listenCurrencyUpdates(ctxEG)
 	})
 
+	log.Debug("starting polling fee rules...")
+	workerGroup.Go(func() error {
+		return c.listenFeeRuleUpdates(ctxEG)
+	})
+
 	log.Debug("getting initial currencies and rates")
 	if err := c.fetchEnabledCurrencies(ctx); err != nil {
 		return err
 	}
 
+	if err := c.loadMarkups(ctx); err != nil {
+		return err
+	}
+
+	if err := c.loadFees(ctx); err != nil {
+		return err
+	}
+
 	currencies := make(map[CurrencyCode]Currency)
 
 	c.currencies.Range(func(key CurrencyCode, value Currency) bool {
@@ -112,13 +193,18 @@ func (c *RateCalculator) Start() error {
 		return true
 	})
 
-	if err := c.fetchRates(ctx, currencies); err != nil {
-		return err
+	_, initialErr := c.fetchRates(ctx, currencies)
+	if initialErr != nil {
+		log.Error(
+			"could not fetch initial rates, restoring them from snapshots",
+			slog.Any("error", initialErr),
+		)
+		c.restoreRates(ctx)
 	}
 
 	log.Debug("starting polling rates...")
 	workerGroup.Go(func() error {
-		return c.pollRates(ctxEG)
+		return c.pollRates(ctxEG, initialErr)
 	})
 
 	if err := workerGroup.Wait(); err != nil && !errors.Is(err, context.Canceled) {
@@ -135,164 +221,220 @@ func (c *RateCalculator) Stop() {
 	}
 }
 
+// Conversion is the result of the conversion along with
+// the rates which were used to calculate it
+type Conversion struct {
+	// Output is the net amount after the fee
+	Output decimal.Decimal
+	Charge Charge
+	// Decimals is the count of decimal places the output was rounded to
+	Decimals int32
+	// Rate is the mid cross rate
+	Rate Rate
+	// Side is the side the output was converted by
+	Side Side
+	// Price is the rate of the side the output was converted by
+	Price decimal.Decimal
+	Bid   decimal.Decimal
+	Ask   decimal.Decimal
+	// Path is the chain of the quotes from base to quote currency
+	Path []Rate
+}
+
+type ConvertRequest struct {
+	Base   string
+	Quote  string
+	Amount decimal.Decimal
+	// Decimals is the count of decimal places of the output,
+	// minor units of the quote currency are used if nil
+	Decimals *int64
+	Rounding RoundingMode
+	Side     Side
+}
+
+// Convert converts the amount by the current rates, the amount is not
+// validated here since v0 accepts any float amount, see ValidateAmount
 func (c *RateCalculator) Convert(
 	ctx context.Context,
-	base, quote string,
-	amount float64,
-	decimals int64,
-) (res float64, err error) {
-	base = strings.ToUpper(base)
-	quote = strings.ToUpper(quote)
+	req ConvertRequest,
+) (res *Conversion, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Error("panic while converting", r)
 			err = ErrServiceInternal
 		}
 	}()
-	baseCurrency, err := c.getCurrency(base)
+
+	return c.convert(c.currentPricing(), req, time.Now())
+}
+
+// convert calculates the conversion by the pricing snapshot
+func (c *RateCalculator) convert(prices pricing, req ConvertRequest, now time.Time) (*Conversion, error) {
+	pair, decimals, err := c.validateRequest(req)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	quoteCurrency, err := c.getCurrency(quote)
+
+	crossRate, path, err := c.crossRate(prices.graph, pair.Base.Code, pair.Quote.Code)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	pair := CurrencyPair{
-		Base:  *baseCurrency,
-		Quote: *quoteCurrency,
+	if err := c.checkRateAge(pair, crossRate, now); err != nil {
+		return nil, err
 	}
 
-	if err := pair.Validate(); err != nil {
-		return 0, err
+	side, err := ParseSide(string(req.Side))
+	if err != nil {
+		return nil, err
 	}
 
-	baseUsdRate, ok := c.ratesInUSD.Load(CurrencyCode(base))
-	if !ok || baseUsdRate.Cmp(decimal.Zero) == 0 {
-		log.Error("zero denominator", slog.Any("currency_code", baseCurrency.Code))
-		return 0, ErrInvalidInternalRate
+	bid, ask := prices.markups.bidAsk(pair, crossRate.Value)
+
+	factor := prices.markups.sideFactor(pair, side)
+	price := crossRate.Value.Mul(factor)
+
+	// the amount is converted by the exact cross rate,
+	// the rounded price is only reported
+	gross := crossRate.convert(req.Amount.Mul(factor), decimals)
+
+	charge := newCharge(prices.fees.schedules(pair), gross, decimals, req.Rounding)
+
+	return &Conversion{
+		Output:   charge.Net,
+		Charge:   charge,
+		Decimals: decimals,
+		Rate:     crossRate,
+		Side:     side,
+		Price:    price,
+		Bid:      bid,
+		Ask:      ask,
+		Path:     path,
+	}, nil
+}
+
+// validateRequest checks the currencies of the request and
+// returns them along with decimal places of the output
+func (c *RateCalculator) validateRequest(req ConvertRequest) (*CurrencyPair, int32, error) {
+	baseCurrency, err := c.getCurrency(req.Base)
+	if err != nil {
+		return nil, 0, err
+	}
+	quoteCurrency, err := c.getCurrency(req.Quote)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	quoteUsdRate, ok := c.ratesInUSD.Load(CurrencyCode(quote))
-	if !ok {
-		return 0, ErrInvalidInternalRate
+	pair := CurrencyPair{
+		Base:  *baseCurrency,
+		Quote: *quoteCurrency,
 	}
 
-	crossRate := quoteUsdRate.Div(baseUsdRate)
+	if err := pair.Validate(c.pairTypesPolicy); err != nil {
+		return nil, 0, err
+	}
 
-	convertAmount := crossRate.Mul(decimal.NewFromFloat(amount))
+	decimals := int64(quoteCurrency.MinorUnits)
+	if req.Decimals != nil {
+		decimals = *req.Decimals
+	}
 
-	res, _ = convertAmount.Round(int32(decimals)).Float64()
+	if err := c.validateDecimals(quoteCurrency, decimals); err != nil {
+		return nil, 0, err
+	}
 
-	return res, nil
+	return &pair, int32(decimals), nil
 }
 
-func (c *RateCalculator) updateCurrencies(currencies []Currency) {
-	for _, currency := range currencies {
-		if currency.IsEnabled {
-			c.currencies.Store(currency.Code, currency)
-		} else {
-			c.currencies.Delete(currency.Code)
-		}
+// checkRateAge checks the rate against the lowest max age of
+// the pair currencies, rates without fetch time are not limited
+func (c *RateCalculator) checkRateAge(pair *CurrencyPair, rate Rate, now time.Time) error {
+	if rate.FetchedAt.IsZero() {
+		return nil
 	}
-}
 
-func (c *RateCalculator) getCurrency(code string) (*Currency, error) {
-	code = strings.ToUpper(code)
+	maxAge := c.maxRateAge(&pair.Base, rate.Stale)
+	if quoteMaxAge := c.maxRateAge(&pair.Quote, rate.Stale); maxAge == 0 || (quoteMaxAge != 0 && quoteMaxAge < maxAge) {
+		maxAge = quoteMaxAge
+	}
 
-	currency, ok := c.currencies.Load(CurrencyCode(code))
-	if !ok {
-		return nil, &ErrCurrencyNotAvailable{
-			Code: CurrencyCode(code),
+	if maxAge != 0 && now.Sub(rate.FetchedAt) > maxAge {
+		return &ErrRateIsTooOld{
+			Pair:      rate.Pair(),
+			FetchedAt: rate.FetchedAt,
+			MaxAge:    maxAge,
 		}
 	}
 
-	return &currency, nil
+	return nil
 }
 
-func (c *RateCalculator) fetchUsdtUsdRate(ctx context.Context) (float64, error) {
-	usdtUsdRates, err := c.client.GetCryptoRates(
-		ctx,
-		[]CurrencyCode{USDT},
-		USD,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("GetCryptoRates(): %w", err)
+// maxRateAge returns max age of the rates configured for the
+// currency type or the global one, zero means no limit. Stale rates
+// restored from the snapshots have their own limit, so the conversions
+// are served during the providers outage
+func (c *RateCalculator) maxRateAge(currency *Currency, stale bool) time.Duration {
+	if stale {
+		return c.RateStaleMaxAge
 	}
 
-	usdtUsdRate, err := usdtUsdRates.Rates[USDT].Float64()
-	if err != nil {
-		log.Error(
-			"invalid USDT/USD rate",
-			slog.String("actual_value", usdtUsdRates.Rates[USDT].String()),
-			slog.Any("error", err),
-		)
-		return 0, fmt.Errorf("invalid USDT/USD rate: %w", err)
+	if maxAge, ok := c.RateMaxAgeByType[string(currency.Type)]; ok {
+		return maxAge
 	}
 
-	return usdtUsdRate, nil
+	return c.RateMaxAge
 }
 
-func (c *RateCalculator) fetchRates(
-	ctx context.Context,
-	currencies map[CurrencyCode]Currency,
-) error {
-	fiatCodes := []CurrencyCode{}
-	cryptoCodes := []CurrencyCode{}
-
-	for code, val := range currencies {
-		switch val.Type {
-		case Crypto:
-			cryptoCodes = append(cryptoCodes, code)
-		case Fiat:
-			fiatCodes = append(fiatCodes, code)
-		}
+// maxDecimals returns the limit of decimal places configured
+// for the currency code or for its type
+func (c *RateCalculator) maxDecimals(currency *Currency) int32 {
+	maxDecimals, ok := c.MaxDecimals[string(currency.Code)]
+	if !ok {
+		maxDecimals, ok = c.MaxDecimals[string(currency.Type)]
 	}
-
-	usdtUsdRate, err := c.fetchUsdtUsdRate(ctx)
-	if err != nil {
-		return fmt.Errorf("getUsdtUsdRate(): %w", err)
+	if !ok {
+		maxDecimals = defaultMaxDecimals
 	}
-	_ = usdtUsdRate
 
-	fiatRates, err := c.client.GetFiatRates(ctx, USD, fiatCodes)
-	if err != nil {
-		return fmt.Errorf("GetFiatRates(): %w", err)
-	}
+	return maxDecimals
+}
 
-	cryptoRates, err := c.client.GetCryptoRates(ctx, cryptoCodes, USDT)
-	if err != nil {
-		return fmt.Errorf("GetCryptoRates(): %w", err)
-	}
+// validateDecimals checks decimals against the limit of the currency
+func (c *RateCalculator) validateDecimals(currency *Currency, decimals int64) error {
+	maxDecimals := c.maxDecimals(currency)
 
-	ratesFloat := map[CurrencyCode]float64{}
-	for code, rateUSD := range fiatRates.Rates {
-		rateFloat, err := rateUSD.Float64()
-		if err != nil {
-			log.Error("invalid rate", slog.String("actual_value", rateUSD.String()), err)
-			return fmt.Errorf("invalid rate from response: %w", err)
+	if decimals < 0 || decimals > int64(maxDecimals) {
+		return &ErrInvalidDecimals{
+			Code:     currency.Code,
+			Decimals: decimals,
+			Max:      maxDecimals,
 		}
-
-		ratesFloat[CurrencyCode(code)] = rateFloat
 	}
 
-	for code, rateUSD := range cryptoRates.Rates {
-		rateFloat, err := rateUSD.Float64()
-		if err != nil {
-			log.Error("invalid rate", slog.String("actual_value", rateUSD.String()), err)
-			return fmt.Errorf("invalid rate from response: %w", err)
-		}
+	return nil
+}
 
-		ratesFloat[code] = 1 / (rateFloat) * usdtUsdRate
+func (c *RateCalculator) updateCurrencies(currencies []Currency) {
+	for _, currency := range currencies {
+		if currency.IsEnabled {
+			c.currencies.Store(currency.Code, currency)
+		} else {
+			c.currencies.Delete(currency.Code)
+		}
 	}
+}
 
-	ratesFloat[USDT] = usdtUsdRate
+func (c *RateCalculator) getCurrency(code string) (*Currency, error) {
+	code = strings.ToUpper(code)
 
-	for code, rateUSD := range ratesFloat {
-		c.ratesInUSD.Store(code, decimal.NewFromFloat(rateUSD))
+	currency, ok := c.currencies.Load(CurrencyCode(code))
+	if !ok {
+		return nil, &ErrCurrencyNotAvailable{
+			Code: CurrencyCode(code),
+		}
 	}
 
-	return nil
+	return &currency, nil
 }
 
 func (c *RateCalculator) fetchEnabledCurrencies(ctx context.Context) error {
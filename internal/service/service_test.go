@@ -0,0 +1,117 @@
+package service
+
+import (
+	"blum-test/common/config"
+	. "blum-test/common/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newRateAgeCalculator() *RateCalculator {
+	return &RateCalculator{
+		Service: config.Service{
+			RateMaxAge: 5 * time.Minute,
+			RateMaxAgeByType: map[string]time.Duration{
+				string(Fiat):   time.Hour,
+				string(Crypto): 2 * time.Minute,
+			},
+			RateStaleMaxAge: 24 * time.Hour,
+		},
+	}
+}
+
+func TestMaxRateAge(t *testing.T) {
+	c := newRateAgeCalculator()
+
+	tests := []struct {
+		name     string
+		currency Currency
+		stale    bool
+		want     time.Duration
+	}{
+		{name: "type override", currency: Currency{Code: "EUR", Type: Fiat}, want: time.Hour},
+		{name: "other type override", currency: Currency{Code: "ETH", Type: Crypto}, want: 2 * time.Minute},
+		{name: "global limit", currency: Currency{Code: "XAU", Type: "METAL"}, want: 5 * time.Minute},
+		{name: "stale limit", currency: Currency{Code: "ETH", Type: Crypto}, stale: true, want: 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.maxRateAge(&tt.currency, tt.stale); got != tt.want {
+				t.Errorf("maxRateAge() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRateAge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	usd := Currency{Code: USD, Type: Fiat}
+	eur := Currency{Code: "EUR", Type: Fiat}
+	eth := Currency{Code: "ETH", Type: Crypto}
+
+	tests := []struct {
+		name string
+		c    *RateCalculator
+		pair CurrencyPair
+		// age is the age of the rate, zero FetchedAt if negative
+		age     time.Duration
+		stale   bool
+		wantErr bool
+	}{
+		{name: "fresh", pair: CurrencyPair{Base: usd, Quote: eur}, age: 30 * time.Minute},
+		{name: "stale by type", pair: CurrencyPair{Base: usd, Quote: eur}, age: 2 * time.Hour, wantErr: true},
+		{name: "lowest limit of the pair", pair: CurrencyPair{Base: eur, Quote: eth}, age: 3 * time.Minute, wantErr: true},
+		{name: "fresh by lowest limit", pair: CurrencyPair{Base: eth, Quote: eur}, age: time.Minute},
+		{name: "limit bound", pair: CurrencyPair{Base: eur, Quote: eth}, age: 2 * time.Minute},
+		{name: "restored rate", pair: CurrencyPair{Base: usd, Quote: eth}, age: 3 * time.Hour, stale: true},
+		{name: "too old restored rate", pair: CurrencyPair{Base: usd, Quote: eth}, age: 25 * time.Hour, stale: true, wantErr: true},
+		// USD to USD rate is not fetched, so it has no age
+		{name: "USD is exempt", pair: CurrencyPair{Base: usd, Quote: usd}, age: -1},
+		{
+			name: "no limits",
+			c:    &RateCalculator{},
+			pair: CurrencyPair{Base: usd, Quote: eth},
+			age:  1000 * time.Hour,
+		},
+		{
+			name: "unlimited type",
+			c: &RateCalculator{
+				Service: config.Service{
+					RateMaxAge:       time.Minute,
+					RateMaxAgeByType: map[string]time.Duration{string(Fiat): 0},
+				},
+			},
+			pair:    CurrencyPair{Base: usd, Quote: eth},
+			age:     2 * time.Minute,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.c
+			if c == nil {
+				c = newRateAgeCalculator()
+			}
+
+			rate := Rate{
+				Base:  tt.pair.Base.Code,
+				Quote: tt.pair.Quote.Code,
+				Stale: tt.stale,
+			}
+			if tt.age >= 0 {
+				rate.FetchedAt = now.Add(-tt.age)
+			}
+
+			err := c.checkRateAge(&tt.pair, rate, now)
+
+			var tooOld *ErrRateIsTooOld
+			if tt.wantErr != errors.As(err, &tooOld) {
+				t.Errorf("checkRateAge() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
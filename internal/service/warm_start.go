@@ -0,0 +1,62 @@
+package service
+
+import (
+	. "blum-test/common/models"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// restoreRates seeds the rates of the enabled currencies which are not
+// fetched yet from the latest persisted snapshots. Restored rates are
+// marked as stale and replaced once fetched from the providers.
+func (c *RateCalculator) restoreRates(ctx context.Context) {
+	codes := []CurrencyCode{}
+	c.currencies.Range(func(code CurrencyCode, _ Currency) bool {
+		if _, ok := c.ratesInUSD.Load(code); !ok && code != USD {
+			codes = append(codes, code)
+		}
+		return true
+	})
+
+	if len(codes) == 0 {
+		return
+	}
+
+	snapshots, err := c.rateRepo.GetRateSnapshotsAt(ctx, codes, time.Now())
+	if err != nil {
+		log.Error("could not get rate snapshots", slog.Any("error", err))
+		return
+	}
+
+	for _, code := range codes {
+		snapshot, ok := snapshots[code]
+		if !ok || !snapshot.RateInUSD.IsPositive() {
+			log.Warn("no rate snapshot to restore", slog.String("currency_code", string(code)))
+			continue
+		}
+
+		// restored quotes connect the currency to USD directly
+		c.quotes.Store(code, &Rate{
+			Base:      USD,
+			Quote:     code,
+			Value:     snapshot.RateInUSD,
+			Providers: strings.Split(snapshot.Provider, ","),
+			Spread:    decimal.Zero,
+			Stale:     true,
+			FetchedAt: snapshot.FetchedAt,
+		})
+
+		log.Info(
+			"rate restored from snapshot",
+			slog.String("currency_code", string(code)),
+			slog.String("rate", snapshot.RateInUSD.String()),
+			slog.Time("fetched_at", snapshot.FetchedAt),
+		)
+	}
+
+	c.updateRatesInUSD()
+}
@@ -9,6 +9,8 @@ CREATE TABLE currencies (
     code VARCHAR(10) NOT NULL UNIQUE,
     is_enabled BOOLEAN NOT NULL DEFAULT true,
     type_id INT NOT NULL,
+    -- ISO 4217 exponent for fiat, chain decimals for crypto
+    minor_units SMALLINT NOT NULL DEFAULT 2 CHECK (minor_units >= 0),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (type_id) REFERENCES currency_types(id)
@@ -28,13 +30,61 @@ INSERT INTO currency_types (name)
 VALUES ('FIAT');
 INSERT INTO currency_types (name)
 VALUES ('CRYPTO');
-INSERT INTO currencies (name, code, is_enabled, type_id)
-VALUES ('Euro', 'EUR', true, 1),
-    ('US Dollar', 'USD', true, 1),
-    ('Chinese Yuan', 'CNY', true, 1),
-    ('Tether', 'USDT', true, 2),
-    ('USD Coin', 'USDC', true, 2),
-    ('Ethereum', 'ETH', true, 2);
+INSERT INTO currencies (name, code, is_enabled, type_id, minor_units)
+VALUES ('Euro', 'EUR', true, 1, 2),
+    ('US Dollar', 'USD', true, 1, 2),
+    ('Chinese Yuan', 'CNY', true, 1, 2),
+    ('Tether', 'USDT', true, 2, 6),
+    ('USD Coin', 'USDC', true, 2, 6),
+    ('Ethereum', 'ETH', true, 2, 18);
+-- история курсов, записывается каждый цикл обновления
+CREATE TABLE rate_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    currency_code VARCHAR(10) NOT NULL,
+    rate_in_usd NUMERIC NOT NULL,
+    provider VARCHAR(255) NOT NULL,
+    fetched_at TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_rate_snapshots_code_fetched_at ON rate_snapshots(currency_code, fetched_at);
+-- наценки к среднему курсу в базисных пунктах, приоритет: пара, валюта, тип
+CREATE TABLE markups (
+    id SERIAL PRIMARY KEY,
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('PAIR', 'CURRENCY', 'TYPE')),
+    -- пара "BASE/QUOTE", код или тип валюты
+    target VARCHAR(255) NOT NULL,
+    bps INT NOT NULL CHECK (bps >= 0 AND bps < 10000),
+    UNIQUE (scope, target)
+);
+-- правила комиссий, ступени по сумме задаются строками с разным min_amount,
+-- суммы в валюте котировки
+CREATE TABLE fee_rules (
+    id SERIAL PRIMARY KEY,
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('PAIR', 'TYPE')),
+    -- пара "BASE/QUOTE" или тип валюты
+    target VARCHAR(255) NOT NULL,
+    -- нижняя граница суммы до комиссии для ступени
+    min_amount NUMERIC NOT NULL DEFAULT 0 CHECK (min_amount >= 0),
+    flat NUMERIC NOT NULL DEFAULT 0 CHECK (flat >= 0),
+    bps INT NOT NULL DEFAULT 0 CHECK (bps >= 0 AND bps <= 10000),
+    min_fee NUMERIC NOT NULL DEFAULT 0 CHECK (min_fee >= 0),
+    max_fee NUMERIC CHECK (max_fee >= min_fee),
+    -- валюты типа различаются, поэтому для типа задается только bps
+    CHECK (scope = 'PAIR' OR (min_amount = 0 AND flat = 0 AND min_fee = 0 AND max_fee IS NULL)),
+    UNIQUE (scope, target, min_amount)
+);
+-- зафиксированные курсы, действуют до expires_at
+CREATE TABLE quotes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    base_code VARCHAR(10) NOT NULL,
+    quote_code VARCHAR(10) NOT NULL,
+    -- сторона клиента, курс зафиксирован с наценкой стороны
+    side VARCHAR(4) NOT NULL CHECK (side IN ('mid', 'sell', 'buy')),
+    rate NUMERIC NOT NULL CHECK (rate > 0),
+    -- расписания комиссий на момент создания котировки
+    fees JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
 -- notification to listen
 CREATE OR REPLACE FUNCTION notify_currency_change() RETURNS trigger AS $$
 DECLARE notification JSON;
@@ -68,4 +118,20 @@ INSERT
     OR
 UPDATE
     OR DELETE ON currencies FOR EACH ROW EXECUTE FUNCTION notify_currency_change();
+-- правила комиссий перечитываются целиком при любом изменении
+CREATE OR REPLACE FUNCTION notify_fee_rule_change() RETURNS trigger AS $$ BEGIN
+PERFORM pg_notify(
+    'fee_rule_events',
+    json_build_object('operation', TG_OP)::text
+);
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+CREATE OR REPLACE TRIGGER trigger_notify_fee_rule_event
+AFTER
+INSERT
+    OR
+UPDATE
+    OR DELETE
+    OR TRUNCATE ON fee_rules FOR EACH STATEMENT EXECUTE FUNCTION notify_fee_rule_change();
 COMMIT;
\ No newline at end of file